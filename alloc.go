@@ -0,0 +1,107 @@
+package ncs
+
+import "sync"
+
+// Allocator allocates the buffers which hold the data of the elements read from FIFOs.
+// It allows the result tensors to be placed in arena allocators, shared memory regions or
+// pinned host memory instead of the Go heap.
+//
+// Alloc must return a buffer of exactly size bytes. Free is called with the buffers returned
+// by Alloc once the tensor holding them is released via Tensor.Release.
+// The buffers of the elements read by calls which have timed out or have been cancelled are never freed.
+// Allocator must be safe for concurrent use by multiple goroutines.
+type Allocator interface {
+	Alloc(size int) []byte
+	Free(buf []byte)
+}
+
+var (
+	// allocMu guards defaultAlloc
+	allocMu sync.RWMutex
+	// defaultAlloc is the package default allocator
+	defaultAlloc Allocator
+)
+
+// SetDefaultAllocator sets the default allocator of the FIFO element buffers.
+// The default applies to all FIFOs which do not have their own allocator set via SetAllocator.
+// Passing nil, which is the default, means the buffers are allocated on the Go heap.
+func SetDefaultAllocator(a Allocator) {
+	allocMu.Lock()
+	defer allocMu.Unlock()
+
+	defaultAlloc = a
+}
+
+// DefaultAllocator returns the package default allocator of the FIFO element buffers
+func DefaultAllocator() Allocator {
+	allocMu.RLock()
+	defer allocMu.RUnlock()
+
+	return defaultAlloc
+}
+
+// elemAllocator returns a if it's not nil, otherwise it returns the package default allocator
+func elemAllocator(a Allocator) Allocator {
+	if a != nil {
+		return a
+	}
+
+	return DefaultAllocator()
+}
+
+// Release returns the tensor data buffer to the allocator which allocated it and sets Data to nil.
+// The data must not be used after the tensor has been released.
+// Release does nothing for the tensors whose data has been allocated on the Go heap.
+func (t *Tensor) Release() {
+	if t == nil || t.alloc == nil {
+		return
+	}
+
+	t.alloc.Free(t.Data)
+	t.Data, t.alloc, t.off = nil, nil, 0
+}
+
+// PoolAllocator is Allocator which recycles the released buffers via sync.Pool.
+// The buffers are pooled by their size, so reading the same sized elements in a loop,
+// e.g. the frames of a camera pipeline, does not allocate once the pool has warmed up.
+// The zero value is ready to use.
+type PoolAllocator struct {
+	// pools maps buffer sizes to *sync.Pool of buffers of that size
+	pools sync.Map
+}
+
+// NewPoolAllocator creates new PoolAllocator and returns it
+func NewPoolAllocator() *PoolAllocator {
+	return &PoolAllocator{}
+}
+
+// pool returns the pool of buffers of the given size
+func (p *PoolAllocator) pool(size int) *sync.Pool {
+	if pool, ok := p.pools.Load(size); ok {
+		return pool.(*sync.Pool)
+	}
+
+	pool, _ := p.pools.LoadOrStore(size, &sync.Pool{
+		New: func() interface{} {
+			buf := make([]byte, size)
+			return &buf
+		},
+	})
+
+	return pool.(*sync.Pool)
+}
+
+// Alloc returns a buffer of size bytes, reusing a released buffer of the same size if there is one.
+// The contents of the reused buffers are not cleared.
+func (p *PoolAllocator) Alloc(size int) []byte {
+	return *p.pool(size).Get().(*[]byte)
+}
+
+// Free returns buf to the pool so it can be reused by subsequent calls to Alloc
+func (p *PoolAllocator) Free(buf []byte) {
+	if len(buf) == 0 {
+		return
+	}
+
+	p.pool(len(buf)).Put(&buf)
+}
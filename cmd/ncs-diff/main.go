@@ -0,0 +1,163 @@
+// ncs-diff compares two saved tensors and reports the maximum and mean absolute difference
+// of their values along with the values which differ the most. It is used to validate the results
+// of Go inference pipelines against the outputs of mvNCCheck or of reference Python models.
+//
+// The tensors can be stored either in NumPy .npy files of float32 or float16 little-endian arrays,
+// or in raw files containing the tensor data as read from NCS FIFO, e.g. the Tensor Data.
+//
+// Usage:
+//
+//	ncs-diff [flags] got want
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/milosgajdos/ncs"
+)
+
+var (
+	dtype   = flag.String("dtype", "fp32", "data type of raw tensor files: fp32 or fp16")
+	top     = flag.Int("top", 10, "number of the most mismatched values to report")
+	tol     = flag.Float64("tol", 0, "maximum tolerated absolute difference; ncs-diff exits with non-zero status if it is exceeded or if NaN or infinite values differ")
+	jsonOut = flag.Bool("json", false, "print the report in JSON format")
+)
+
+// npyMagic is the magic string every .npy file starts with
+const npyMagic = "\x93NUMPY"
+
+// parseDataType parses data type name into FifoDataType
+func parseDataType(name string) (ncs.FifoDataType, error) {
+	switch strings.ToLower(name) {
+	case "fp32", "float32", "<f4":
+		return ncs.FifoFP32, nil
+	case "fp16", "float16", "<f2":
+		return ncs.FifoFP16, nil
+	default:
+		return 0, fmt.Errorf("unsupported data type %q", name)
+	}
+}
+
+// readNpy decodes the contents of .npy file and returns the raw array data along with its data type
+func readNpy(data []byte) ([]byte, ncs.FifoDataType, error) {
+	if len(data) < 10 || string(data[:6]) != npyMagic {
+		return nil, 0, fmt.Errorf("invalid .npy file")
+	}
+
+	var hdrLen, off int
+	switch data[6] {
+	case 1:
+		hdrLen, off = int(binary.LittleEndian.Uint16(data[8:10])), 10
+	case 2, 3:
+		if len(data) < 12 {
+			return nil, 0, fmt.Errorf("invalid .npy file")
+		}
+		hdrLen, off = int(binary.LittleEndian.Uint32(data[8:12])), 12
+	default:
+		return nil, 0, fmt.Errorf("unsupported .npy version %d", data[6])
+	}
+
+	if len(data) < off+hdrLen {
+		return nil, 0, fmt.Errorf("invalid .npy header")
+	}
+	hdr := string(data[off : off+hdrLen])
+
+	if strings.Contains(hdr, "'fortran_order': True") {
+		return nil, 0, fmt.Errorf("fortran ordered arrays are not supported")
+	}
+
+	i := strings.Index(hdr, "'descr':")
+	if i < 0 {
+		return nil, 0, fmt.Errorf("missing .npy data type")
+	}
+	descr := strings.TrimSpace(hdr[i+len("'descr':"):])
+	descr = strings.Trim(strings.SplitN(descr, ",", 2)[0], " '")
+
+	dt, err := parseDataType(descr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return data[off+hdrLen:], dt, nil
+}
+
+// readTensor reads the tensor stored in path and decodes it into float32 values
+func readTensor(path string, rawType ncs.FifoDataType) ([]float32, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dt := rawType
+	if strings.ToLower(filepath.Ext(path)) == ".npy" || bytes.HasPrefix(data, []byte(npyMagic)) {
+		if data, dt, err = readNpy(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	t := &ncs.Tensor{Data: data}
+
+	return t.Values(dt)
+}
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] got want\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	rawType, err := parseDataType(*dtype)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	got, err := readTensor(flag.Arg(0), rawType)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	want, err := readTensor(flag.Arg(1), rawType)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	diff, err := ncs.Compare(got, want, *top)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diff); err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+	} else {
+		fmt.Printf("values:       %d\n", diff.Count)
+		fmt.Printf("max abs err:  %g\n", diff.MaxAbsErr)
+		fmt.Printf("mean abs err: %g\n", diff.MeanAbsErr)
+		fmt.Printf("non-finite:   %d\n", diff.NonFinite)
+		if len(diff.Top) > 0 {
+			fmt.Printf("top mismatches:\n")
+			for _, m := range diff.Top {
+				fmt.Printf("  [%d] got %g, want %g, abs err %g\n", m.Index, m.Got, m.Want, m.AbsErr)
+			}
+		}
+	}
+
+	if *tol > 0 && (diff.MaxAbsErr > *tol || diff.NonFinite > 0) {
+		os.Exit(1)
+	}
+}
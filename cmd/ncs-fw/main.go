@@ -0,0 +1,139 @@
+// ncs-fw reports the NCSDK API version, the firmware files the NCSDK boots the devices with
+// and the hardware, firmware and MVTensor versions of every attached NCS device.
+// It flags the devices which fail to boot because the firmware file is missing and
+// the firmware versions which are not supported by the installed NCSDK.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/milosgajdos/ncs"
+)
+
+var (
+	fwDir = flag.String("fwdir", "/usr/local/lib/mvnc", "directory the NCSDK loads the device firmware files from")
+)
+
+// fwFiles maps device hardware versions to the names of the firmware files they boot with
+var fwFiles = map[ncs.DeviceHWVersion]string{
+	ncs.MA2450: "MvNCAPI-ma2450.mvcmd",
+	ncs.MA2480: "MvNCAPI-ma2480.mvcmd",
+}
+
+// version formats version numbers as dot separated string
+func version(val []uint32) string {
+	parts := make([]string, len(val))
+	for i, v := range val {
+		parts[i] = fmt.Sprint(v)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// report prints the firmware information of the device described by dd.
+// It returns the number of problems found.
+func report(dd ncs.DeviceDesc) int {
+	fmt.Printf("device %d: %s\n", dd.Index, dd.Name)
+
+	dev, err := dd.Open()
+	if err != nil {
+		if errors.Is(err, ncs.ErrCmdNotFound) {
+			fmt.Printf("  boot:      FAILED: firmware file not found in %s\n", *fwDir)
+		} else {
+			fmt.Printf("  boot:      FAILED: %s\n", err)
+		}
+		return 1
+	}
+	defer dev.Destroy()
+	defer dev.Close()
+
+	fmt.Printf("  boot:      ok\n")
+
+	problems := 0
+
+	if hw, err := ncs.GetOption[uint](dev, ncs.RODeviceHWVersion); err == nil {
+		hwVersion := ncs.DeviceHWVersion(hw)
+		fmt.Printf("  hardware:  %s\n", hwVersion)
+		if name, ok := fwFiles[hwVersion]; ok {
+			fmt.Printf("  fw file:   %s\n", filepath.Join(*fwDir, name))
+		}
+	} else {
+		fmt.Printf("  hardware:  unknown: %s\n", err)
+		problems++
+	}
+
+	if fw, err := ncs.GetOption[[]uint32](dev, ncs.RODeviceFirmwareVersion); err == nil {
+		fmt.Printf("  firmware:  %s\n", version(fw))
+	} else {
+		fmt.Printf("  firmware:  unknown: %s\n", err)
+		problems++
+	}
+
+	if mvt, err := ncs.GetOption[[]uint32](dev, ncs.RODeviceMVTensorVersion); err == nil {
+		fmt.Printf("  mvtensor:  %s\n", version(mvt))
+	} else {
+		fmt.Printf("  mvtensor:  unknown: %s\n", err)
+		problems++
+	}
+
+	if err := ncs.CheckCompat(dev); err != nil {
+		fmt.Printf("  compat:    MISMATCH: %s\n", err)
+		problems++
+	} else {
+		fmt.Printf("  compat:    ok\n")
+	}
+
+	return problems
+}
+
+func main() {
+	flag.Parse()
+
+	problems := 0
+
+	api, err := ncs.APIVersion()
+	if err != nil {
+		log.Fatalf("Error: failed to query NCSDK API version: %s", err)
+	}
+	fmt.Printf("NCSDK API: %s\n", api)
+
+	if err := ncs.CheckCompat(nil); err != nil {
+		fmt.Printf("  compat:    MISMATCH: %s\n", err)
+		problems++
+	}
+
+	fmt.Printf("firmware files in %s:\n", *fwDir)
+	for _, hw := range []ncs.DeviceHWVersion{ncs.MA2450, ncs.MA2480} {
+		path := filepath.Join(*fwDir, fwFiles[hw])
+		if _, err := os.Stat(path); err != nil {
+			fmt.Printf("  %s: %s missing\n", hw, fwFiles[hw])
+			continue
+		}
+		fmt.Printf("  %s: %s\n", hw, fwFiles[hw])
+	}
+
+	devices, err := ncs.Devices()
+	if err != nil {
+		log.Fatalf("Error: failed to enumerate devices: %s", err)
+	}
+
+	if len(devices) == 0 {
+		fmt.Printf("no devices found\n")
+		os.Exit(1)
+	}
+
+	for _, dd := range devices {
+		problems += report(dd)
+	}
+
+	if problems > 0 {
+		fmt.Printf("%d problems found\n", problems)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,240 @@
+// ncs-soak runs continuous inference on NCS device and periodically samples host process RSS,
+// device memory used and device temperature. It flags the metrics which keep growing over
+// the sampling window, which usually points to a memory leak or a thermal problem.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milosgajdos/ncs"
+)
+
+var (
+	graphPath = flag.String("graph", "graph", "path to compiled NCS graph file")
+	devIndex  = flag.Int("device", 0, "index of NCS device")
+	duration  = flag.Duration("duration", time.Hour, "duration of the soak test")
+	interval  = flag.Duration("interval", 10*time.Second, "metrics sampling interval")
+	window    = flag.Int("window", 6, "number of consecutive samples a metric has to grow to be flagged")
+)
+
+// metric stores the recent samples of a monitored value
+type metric struct {
+	name    string
+	unit    string
+	samples []float64
+}
+
+// add appends sample v and drops the samples which fall out of the window
+func (m *metric) add(v float64) {
+	m.samples = append(m.samples, v)
+	if len(m.samples) > *window {
+		m.samples = m.samples[1:]
+	}
+}
+
+// growing returns true if the metric has grown monotonically over the whole window
+func (m *metric) growing() bool {
+	if len(m.samples) < *window || *window < 2 {
+		return false
+	}
+
+	for i := 1; i < len(m.samples); i++ {
+		if m.samples[i] < m.samples[i-1] {
+			return false
+		}
+	}
+
+	return m.samples[len(m.samples)-1] > m.samples[0]
+}
+
+// last returns the latest sample of the metric
+func (m *metric) last() float64 {
+	return m.samples[len(m.samples)-1]
+}
+
+// hostRSS returns resident set size of the current process in kilobytes
+func hostRSS() (float64, error) {
+	file, err := os.Open("/proc/self/status")
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "VmRSS:" {
+			return strconv.ParseFloat(fields[1], 64)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("VmRSS not found")
+}
+
+// deviceMemUsed returns the memory in use on the device in bytes
+func deviceMemUsed(dev *ncs.Device) (float64, error) {
+	val, err := ncs.GetOption[uint](dev, ncs.RODeviceMemoryUsed)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(val), nil
+}
+
+// deviceTemp returns the highest device temperature in degrees Celsius
+func deviceTemp(dev *ncs.Device) (float64, error) {
+	val, err := ncs.GetOption[[]float32](dev, ncs.RODeviceThermalStats)
+	if err != nil {
+		return 0, err
+	}
+
+	max := float32(0)
+	for _, t := range val {
+		if t > max {
+			max = t
+		}
+	}
+
+	return float64(max), nil
+}
+
+// inputSize returns the element size of FIFO f in bytes
+func inputSize(f *ncs.Fifo) (int, error) {
+	val, err := ncs.GetOption[uint](f, ncs.ROFifoElemDataSize)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(val), nil
+}
+
+func main() {
+	flag.Parse()
+
+	var err error
+	defer func() {
+		if err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	dev, e := ncs.NewDevice(*devIndex)
+	if e != nil {
+		err = e
+		return
+	}
+	defer dev.Destroy()
+
+	if err = dev.Open(); err != nil {
+		return
+	}
+	defer dev.Close()
+
+	graph, e := ncs.NewGraph("SoakGraph")
+	if e != nil {
+		err = e
+		return
+	}
+	defer graph.Destroy()
+
+	graphData, e := ioutil.ReadFile(*graphPath)
+	if e != nil {
+		err = e
+		return
+	}
+
+	queue, e := graph.AllocateWithFifosDefault(dev, graphData)
+	if e != nil {
+		err = e
+		return
+	}
+	defer queue.In.Destroy()
+	defer queue.Out.Destroy()
+
+	size, e := inputSize(queue.In)
+	if e != nil {
+		err = e
+		return
+	}
+	input := make([]byte, size)
+
+	metrics := []*metric{
+		{name: "host RSS", unit: "kB"},
+		{name: "device memory used", unit: "B"},
+		{name: "device temperature", unit: "C"},
+	}
+	samplers := []func() (float64, error){
+		hostRSS,
+		func() (float64, error) { return deviceMemUsed(dev) },
+		func() (float64, error) { return deviceTemp(dev) },
+	}
+
+	log.Printf("Running soak test for %s, sampling every %s", *duration, *interval)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	inferences := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Soak test finished after %s: %d inferences", time.Since(start).Round(time.Second), inferences)
+			return
+		case <-ticker.C:
+			for i, m := range metrics {
+				v, e := samplers[i]()
+				if e != nil {
+					log.Printf("Failed to sample %s: %s", m.name, e)
+					continue
+				}
+				m.add(v)
+				log.Printf("%s: %.1f %s", m.name, m.last(), m.unit)
+				if m.growing() {
+					log.Printf("WARNING: %s has been growing for the last %d samples", m.name, *window)
+				}
+			}
+			log.Printf("inferences: %d", inferences)
+		default:
+		}
+
+		if e := graph.QueueInferenceWithFifoElemContext(ctx, queue, input, nil); e != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			err = e
+			return
+		}
+
+		if _, e := queue.Out.ReadElemContext(ctx); e != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			err = e
+			return
+		}
+
+		inferences++
+	}
+}
@@ -0,0 +1,307 @@
+// ncs-stress deliberately abuses NCS device: it rapidly opens and closes the device, overfills the inbound FIFO,
+// allocates graphs concurrently and closes the device in the middle of running inferences.
+// It verifies the handles recover from the abuse and that no handles are leaked, which helps
+// to reproduce intermittent USB failures and validate the error recovery machinery.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/milosgajdos/ncs"
+)
+
+var (
+	graphPath  = flag.String("graph", "graph", "path to compiled NCS graph file")
+	devIndex   = flag.Int("device", 0, "index of NCS device")
+	iterations = flag.Int("iterations", 10, "number of iterations of every scenario")
+	workers    = flag.Int("workers", 4, "number of goroutines allocating graphs concurrently")
+	timeout    = flag.Duration("timeout", 2*time.Second, "timeout of the potentially blocking NCS calls")
+	scenarios  = flag.String("scenarios", "openclose,overfill,concurrent,reset", "comma separated list of scenarios to run")
+)
+
+// scenario is a stress test scenario run on a device with index idx
+type scenario func(ctx context.Context, idx int, graphData []byte) error
+
+// openDevice creates and opens the device with index idx
+func openDevice(ctx context.Context, idx int) (*ncs.Device, error) {
+	dev, err := ncs.NewDevice(idx, ncs.WithTimeout(*timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dev.OpenContext(ctx); err != nil {
+		dev.Destroy()
+		return nil, err
+	}
+
+	return dev, nil
+}
+
+// allocGraph creates graph with the given name and allocates it along with its FIFOs on dev
+func allocGraph(dev *ncs.Device, name string, graphData []byte) (*ncs.Graph, *ncs.FifoQueue, error) {
+	graph, err := ncs.NewGraph(name, ncs.WithTimeout(*timeout))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queue, err := graph.AllocateWithFifosDefault(dev, graphData)
+	if err != nil {
+		graph.Destroy()
+		return nil, nil, err
+	}
+
+	return graph, queue, nil
+}
+
+// inputSize returns the element size of FIFO f in bytes
+func inputSize(f *ncs.Fifo) (int, error) {
+	val, err := ncs.GetOption[uint](f, ncs.ROFifoElemDataSize)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(val), nil
+}
+
+// openClose rapidly opens and closes the device
+func openClose(ctx context.Context, idx int, graphData []byte) error {
+	for i := 0; i < *iterations; i++ {
+		dev, err := openDevice(ctx, idx)
+		if err != nil {
+			return fmt.Errorf("iteration %d: %w", i, err)
+		}
+
+		if err := dev.Close(); err != nil {
+			dev.Destroy()
+			return fmt.Errorf("iteration %d: %w", i, err)
+		}
+
+		if err := dev.Destroy(); err != nil {
+			return fmt.Errorf("iteration %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// overfill writes more elements into the inbound FIFO than it can hold without queueing any inferences.
+// The writes which exceed the FIFO capacity must fail rather than hang.
+func overfill(ctx context.Context, idx int, graphData []byte) error {
+	dev, err := openDevice(ctx, idx)
+	if err != nil {
+		return err
+	}
+	defer dev.Destroy()
+
+	graph, queue, err := allocGraph(dev, "StressOverfill", graphData)
+	if err != nil {
+		return err
+	}
+	defer graph.Destroy()
+	defer queue.Close()
+
+	capacity, err := ncs.GetOption[uint](queue.In, ncs.ROFifoCapacity)
+	if err != nil {
+		return err
+	}
+
+	size, err := inputSize(queue.In)
+	if err != nil {
+		return err
+	}
+	input := make([]byte, size)
+
+	failed := 0
+	for i := 0; i < int(capacity)+*iterations; i++ {
+		if err := queue.In.WriteElemContext(ctx, input, nil); err != nil {
+			if !errors.Is(err, ncs.ErrTimeout) && !errors.Is(err, ncs.ErrOutOfMemory) && !errors.Is(err, ncs.ErrBusy) {
+				return fmt.Errorf("write %d: %w", i, err)
+			}
+			failed++
+		}
+	}
+	log.Printf("overfill: %d of %d writes into FIFO of capacity %d failed", failed, int(capacity)+*iterations, capacity)
+
+	return nil
+}
+
+// concurrent allocates graphs on the same device from multiple goroutines at the same time
+func concurrent(ctx context.Context, idx int, graphData []byte) error {
+	dev, err := openDevice(ctx, idx)
+	if err != nil {
+		return err
+	}
+	defer dev.Destroy()
+
+	for i := 0; i < *iterations; i++ {
+		var wg sync.WaitGroup
+		errs := make(chan error, *workers)
+
+		for w := 0; w < *workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+
+				graph, queue, err := allocGraph(dev, fmt.Sprintf("StressConcurrent%d", w), graphData)
+				if err != nil {
+					errs <- fmt.Errorf("worker %d: %w", w, err)
+					return
+				}
+				queue.Close()
+				graph.Destroy()
+			}(w)
+		}
+
+		wg.Wait()
+		close(errs)
+
+		// the device may run out of memory for the graphs, but the failures must be reported cleanly
+		for err := range errs {
+			if !errors.Is(err, ncs.ErrOutOfMemory) && !errors.Is(err, ncs.ErrBusy) {
+				return fmt.Errorf("iteration %d: %w", i, err)
+			}
+			log.Printf("concurrent: iteration %d: %s", i, err)
+		}
+	}
+
+	return nil
+}
+
+// reset closes the device in the middle of running inferences and verifies the graph and FIFO handles
+// report they are stale, then it reopens the device and checks the inferences succeed again.
+func reset(ctx context.Context, idx int, graphData []byte) error {
+	dev, err := openDevice(ctx, idx)
+	if err != nil {
+		return err
+	}
+	defer dev.Destroy()
+
+	for i := 0; i < *iterations; i++ {
+		graph, queue, err := allocGraph(dev, "StressReset", graphData)
+		if err != nil {
+			return fmt.Errorf("iteration %d: %w", i, err)
+		}
+
+		size, err := inputSize(queue.In)
+		if err != nil {
+			queue.Close()
+			graph.Destroy()
+			return fmt.Errorf("iteration %d: %w", i, err)
+		}
+		input := make([]byte, size)
+
+		if err := graph.QueueInferenceWithFifoElemContext(ctx, queue, input, nil); err != nil {
+			queue.Close()
+			graph.Destroy()
+			return fmt.Errorf("iteration %d: %w", i, err)
+		}
+
+		// close the device without reading the result
+		if err := dev.Close(); err != nil {
+			queue.Close()
+			graph.Destroy()
+			return fmt.Errorf("iteration %d: %w", i, err)
+		}
+
+		_, readErr := queue.Out.ReadElemContext(ctx)
+		queueErr := graph.QueueInferenceWithFifoElemContext(ctx, queue, input, nil)
+
+		queue.Close()
+		graph.Destroy()
+
+		if !errors.Is(readErr, ncs.ErrStaleHandle) || !errors.Is(queueErr, ncs.ErrStaleHandle) {
+			return fmt.Errorf("iteration %d: expected stale handle errors, got read: %v, queue: %v", i, readErr, queueErr)
+		}
+
+		if err := dev.OpenContext(ctx); err != nil {
+			return fmt.Errorf("iteration %d: reopening device: %w", i, err)
+		}
+
+		graph, queue, err = allocGraph(dev, "StressReset", graphData)
+		if err != nil {
+			return fmt.Errorf("iteration %d: reallocating graph: %w", i, err)
+		}
+
+		err = graph.QueueInferenceWithFifoElemContext(ctx, queue, input, nil)
+		if err == nil {
+			var t *ncs.Tensor
+			t, err = queue.Out.ReadElemContext(ctx)
+			t.Release()
+		}
+
+		queue.Close()
+		graph.Destroy()
+
+		if err != nil {
+			return fmt.Errorf("iteration %d: inference after reset: %w", i, err)
+		}
+	}
+
+	return dev.Close()
+}
+
+func main() {
+	flag.Parse()
+
+	var err error
+	defer func() {
+		if err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+	}()
+
+	all := map[string]scenario{
+		"openclose":  openClose,
+		"overfill":   overfill,
+		"concurrent": concurrent,
+		"reset":      reset,
+	}
+
+	graphData, e := ioutil.ReadFile(*graphPath)
+	if e != nil {
+		err = e
+		return
+	}
+
+	ncs.SetLeakDetection(true)
+	ctx := context.Background()
+
+	failed := 0
+	for _, name := range strings.Split(*scenarios, ",") {
+		run, ok := all[strings.TrimSpace(name)]
+		if !ok {
+			err = fmt.Errorf("unknown scenario %q", name)
+			return
+		}
+
+		log.Printf("Running scenario %s", name)
+		start := time.Now()
+		if e := run(ctx, *devIndex, graphData); e != nil {
+			log.Printf("FAIL: scenario %s: %s", name, e)
+			failed++
+			// make sure the following scenarios start from scratch
+			if e := ncs.Shutdown(ctx); e != nil {
+				log.Printf("Failed to shut down handles: %s", e)
+			}
+			continue
+		}
+		log.Printf("PASS: scenario %s (%s)", name, time.Since(start).Round(time.Millisecond))
+	}
+
+	for _, l := range ncs.CheckLeaks() {
+		log.Printf("LEAK: %s %s created at:\n%s", l.Resource, l.Name, l.Stack)
+		failed++
+	}
+
+	if failed > 0 {
+		err = fmt.Errorf("%d failures", failed)
+	}
+}
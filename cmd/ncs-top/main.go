@@ -0,0 +1,150 @@
+// ncs-top is a live terminal dashboard of all the attached NCS devices.
+// It opens every device and periodically refreshes its temperature, thermal throttling level,
+// memory usage and the number of allocated graphs and FIFOs.
+//
+// NCS devices can only be opened by a single process, so ncs-top can not monitor
+// the devices which are in use by other processes. Such devices are reported as unavailable.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/milosgajdos/ncs"
+)
+
+var (
+	interval = flag.Duration("interval", time.Second, "refresh interval")
+)
+
+// stats are the device statistics displayed on the dashboard
+type stats struct {
+	temp     float32
+	throttle uint
+	memUsed  uint
+	memSize  uint
+	graphs   uint
+	fifos    uint
+}
+
+// throttleLevel returns human readable thermal throttling level
+func throttleLevel(level uint) string {
+	switch level {
+	case 0:
+		return "none"
+	case 1:
+		return "lower"
+	case 2:
+		return "higher"
+	default:
+		return fmt.Sprintf("unknown(%d)", level)
+	}
+}
+
+// sample queries the current statistics of device d
+func sample(d *ncs.Device) (*stats, error) {
+	var s stats
+
+	temps, err := ncs.GetOption[[]float32](d, ncs.RODeviceThermalStats)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range temps {
+		if t > s.temp {
+			s.temp = t
+		}
+	}
+
+	if s.throttle, err = ncs.GetOption[uint](d, ncs.RODeviceThermalThrottle); err != nil {
+		return nil, err
+	}
+	if s.memUsed, err = ncs.GetOption[uint](d, ncs.RODeviceMemoryUsed); err != nil {
+		return nil, err
+	}
+	if s.memSize, err = ncs.GetOption[uint](d, ncs.RODeviceMemorySize); err != nil {
+		return nil, err
+	}
+	if s.graphs, err = ncs.GetOption[uint](d, ncs.RODeviceAllocatedGraphCount); err != nil {
+		return nil, err
+	}
+	if s.fifos, err = ncs.GetOption[uint](d, ncs.RODeviceAllocatedFifoCount); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// render clears the terminal and writes the dashboard to w
+func render(w io.Writer, devices []ncs.DeviceDesc, handles map[int]*ncs.Device) {
+	// move the cursor to the top left corner and clear the screen
+	fmt.Fprint(w, "\033[H\033[2J")
+	fmt.Fprintf(w, "ncs-top - %s - %d devices\n\n", time.Now().Format("15:04:05"), len(devices))
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "IDX\tNAME\tTEMP\tTHROTTLE\tMEMORY\tGRAPHS\tFIFOS")
+
+	for _, dd := range devices {
+		d, ok := handles[dd.Index]
+		if !ok {
+			fmt.Fprintf(tw, "%d\t%s\tunavailable\t\t\t\t\n", dd.Index, dd.Name)
+			continue
+		}
+
+		s, err := sample(d)
+		if err != nil {
+			fmt.Fprintf(tw, "%d\t%s\terror: %s\t\t\t\t\n", dd.Index, dd.Name, err)
+			continue
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%.1fC\t%s\t%d/%d MiB\t%d\t%d\n", dd.Index, dd.Name, s.temp, throttleLevel(s.throttle),
+			s.memUsed>>20, s.memSize>>20, s.graphs, s.fifos)
+	}
+
+	tw.Flush()
+}
+
+func main() {
+	flag.Parse()
+
+	devices, err := ncs.Devices()
+	if err != nil {
+		log.Fatalf("Error: failed to enumerate devices: %s", err)
+	}
+
+	if len(devices) == 0 {
+		log.Fatalf("Error: no devices found")
+	}
+
+	handles := make(map[int]*ncs.Device)
+	for _, dd := range devices {
+		d, err := dd.Open()
+		if err != nil {
+			continue
+		}
+		handles[dd.Index] = d
+	}
+	defer ncs.Shutdown(context.Background())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		render(os.Stdout, devices, handles)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
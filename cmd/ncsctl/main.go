@@ -0,0 +1,259 @@
+// ncsctl is a command line tool for working with NCS devices.
+//
+// The infer command runs a single inference of a compiled graph on an image and prints the results as JSON:
+// the top-k predictions of classification models or the detections of SSD detection models.
+// The image is resized to the graph input size, optionally converted to BGR and normalized as
+// (pixel - mean) * scale before it is sent to the device. It is useful for quick model sanity checks.
+//
+// Usage:
+//
+//	ncsctl infer --graph model.graph --labels labels.txt image.jpg
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/milosgajdos/ncs"
+	"github.com/milosgajdos/ncs/labels"
+	"github.com/milosgajdos/ncs/preprocess"
+	"github.com/milosgajdos/ncs/results"
+	xdraw "golang.org/x/image/draw"
+)
+
+// usage prints the tool usage
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags]\n\nCommands:\n  infer    run inference on an image and print the results as JSON\n", os.Args[0])
+}
+
+// readLabels reads the labels from file path or returns the built-in label set with the given name
+func readLabels(path string) ([]string, error) {
+	switch strings.ToLower(path) {
+	case "":
+		return nil, nil
+	case "imagenet":
+		return labels.ImageNet(), nil
+	case "voc":
+		return labels.VOC(), nil
+	case "coco":
+		return labels.COCO(), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	return lines, scanner.Err()
+}
+
+// readImage reads and decodes the image stored in path
+func readImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+
+	return img, err
+}
+
+// rgb resizes img to width x height and returns its interleaved RGB samples
+func rgb(img image.Image, width, height int) []byte {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.BiLinear.Scale(dst, dst.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+
+	buf := make([]byte, 0, 3*width*height)
+	for i := 0; i < len(dst.Pix); i += 4 {
+		buf = append(buf, dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2])
+	}
+
+	return buf
+}
+
+// decodeSSD decodes SSD detection output: the number of detections followed by 7 values per detection
+// starting at index 7: image id, class index, score and normalized xmin, ymin, xmax, ymax coordinates
+func decodeSSD(out []float32, names []string, threshold float32) results.Detections {
+	dets := results.Detections{}
+	if len(out) == 0 {
+		return dets
+	}
+
+	for i := 0; i < int(out[0]); i++ {
+		idx := 7 + i*7
+		if idx+7 > len(out) {
+			break
+		}
+
+		v := out[idx : idx+7]
+
+		finite := true
+		for _, f := range v {
+			if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+				finite = false
+			}
+		}
+		if !finite || v[2] < threshold {
+			continue
+		}
+
+		det := results.Detection{
+			Index: int(v[1]),
+			Score: v[2],
+			Box:   results.Box{XMin: v[3], YMin: v[4], XMax: v[5], YMax: v[6]},
+		}
+		if det.Index >= 0 && det.Index < len(names) {
+			det.Label = names[det.Index]
+		}
+
+		dets = append(dets, det)
+	}
+
+	return dets
+}
+
+// infer runs the infer command
+func infer(args []string) error {
+	fs := flag.NewFlagSet("infer", flag.ExitOnError)
+	graphPath := fs.String("graph", "graph", "path to compiled NCS graph file")
+	labelsPath := fs.String("labels", "", "path to labels file, or one of the built-in label sets: imagenet, voc, coco")
+	devIndex := fs.Int("device", 0, "index of NCS device")
+	top := fs.Int("top", 5, "number of top predictions to print")
+	mean := fs.Float64("mean", 0, "mean subtracted from the pixel values")
+	scale := fs.Float64("scale", 1, "scale the mean centered pixel values are multiplied by")
+	bgr := fs.Bool("bgr", false, "feed the image in BGR channel order, as expected by Caffe models")
+	ssd := fs.Bool("ssd", false, "decode the output as SSD detections rather than class scores")
+	threshold := fs.Float64("threshold", 0.5, "minimum score of the printed SSD detections")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected exactly one image, got %d", fs.NArg())
+	}
+
+	names, err := readLabels(*labelsPath)
+	if err != nil {
+		return err
+	}
+
+	img, err := readImage(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	graphData, err := ioutil.ReadFile(*graphPath)
+	if err != nil {
+		return err
+	}
+
+	dev, err := ncs.NewDevice(*devIndex)
+	if err != nil {
+		return err
+	}
+	defer dev.Destroy()
+
+	if err := dev.Open(); err != nil {
+		return err
+	}
+	defer dev.Close()
+
+	graph, err := ncs.NewGraph("NcsctlGraph")
+	if err != nil {
+		return err
+	}
+	defer graph.Destroy()
+
+	queue, err := graph.AllocateWithFifosDefault(dev, graphData)
+	if err != nil {
+		return err
+	}
+	defer queue.Close()
+
+	descs, err := ncs.GetOption[[]ncs.TensorDesc](graph, ncs.ROGraphInputTensorDesc)
+	if err != nil {
+		return err
+	}
+	if len(descs) == 0 {
+		return fmt.Errorf("graph has no inputs")
+	}
+	in := descs[0]
+
+	if in.Channels != 3 {
+		return fmt.Errorf("unsupported graph input: expected 3 channels, got %d", in.Channels)
+	}
+
+	frame := rgb(img, int(in.Width), int(in.Height))
+	if *bgr {
+		if err := preprocess.SwapRB(frame); err != nil {
+			return err
+		}
+	}
+
+	input, err := preprocess.Normalize(float32(*mean), float32(*scale))(nil, frame)
+	if err != nil {
+		return err
+	}
+
+	res, err := ncs.Infer(graph, queue, input, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer res.Release()
+
+	t := &ncs.Tensor{Data: res.Data}
+	out, err := t.Values(ncs.FifoFP32)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if *ssd {
+		return enc.Encode(decodeSSD(out, names, float32(*threshold)))
+	}
+
+	return enc.Encode(results.New(out, names).Top(*top))
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "infer":
+		err = infer(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		usage()
+		return
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
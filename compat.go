@@ -0,0 +1,147 @@
+package ncs
+
+// #cgo LDFLAGS: -lmvnc
+/*
+#include <ncs.h>
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// SupportedAPIMajor is the major version of the NCSDK API supported by this package
+	SupportedAPIMajor = 2
+	// SupportedFirmwareMajor is the major version of the device firmware supported by this package
+	SupportedFirmwareMajor = 2
+)
+
+// CompatError is returned when the version of the linked NCSDK API library
+// or the version of the device firmware is not supported by this package.
+type CompatError struct {
+	// Component is the incompatible component: either "NCSDK API" or "device firmware"
+	Component string
+	// Version is the version of the incompatible component
+	Version VersionNumber
+	// Want is the supported major version of the component
+	Want uint
+}
+
+// Error implements error interface
+func (e *CompatError) Error() string {
+	return fmt.Sprintf("Unsupported %s version %s, supported major version is %d: "+
+		"install NCSDK %d.x and make sure the device runs the firmware shipped with it; "+
+		"see https://movidius.github.io/ncsdk/install.html",
+		e.Component, e.Version, e.Want, SupportedAPIMajor)
+}
+
+// Unwrap returns ErrUnsupportedFeature
+func (e *CompatError) Unwrap() error {
+	return ErrUnsupportedFeature
+}
+
+// VersionNumber is a version as reported by NCSDK: major, minor, hotfix and release candidate numbers
+// for the NCSDK API, or major, minor, hardware type and build numbers for the device firmware.
+type VersionNumber [VersionMaxSize]uint
+
+// newVersionNumber returns VersionNumber from the version decoded from NCSDK option
+func newVersionNumber(val []uint32) VersionNumber {
+	var v VersionNumber
+	for i := 0; i < len(v) && i < len(val); i++ {
+		v[i] = uint(val[i])
+	}
+
+	return v
+}
+
+// Major returns the major version number
+func (v VersionNumber) Major() uint {
+	return v[0]
+}
+
+// Minor returns the minor version number
+func (v VersionNumber) Minor() uint {
+	return v[1]
+}
+
+// AtLeast returns true if the version is at least major.minor
+func (v VersionNumber) AtLeast(major, minor uint) bool {
+	return v.Major() > major || (v.Major() == major && v.Minor() >= minor)
+}
+
+// String implements fmt.Stringer interface
+func (v VersionNumber) String() string {
+	parts := make([]string, len(v))
+	for i, n := range v {
+		parts[i] = fmt.Sprint(n)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// MarshalText implements encoding.TextMarshaler interface
+func (v VersionNumber) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// APIVersion returns the version of the linked NCSDK API library.
+// It returns error if it fails to query the version.
+func APIVersion() (VersionNumber, error) {
+	data, err := GetGlobalOption(ROGlobalAPIVersion)
+	if err != nil {
+		return VersionNumber{}, err
+	}
+
+	val, err := ROGlobalAPIVersion.Decode(data)
+	if err != nil {
+		return VersionNumber{}, err
+	}
+
+	return newVersionNumber(val.([]uint32)), nil
+}
+
+// firmwareVersion queries the version of the firmware running on device d
+func firmwareVersion(d *Device) (VersionNumber, error) {
+	data, err := d.GetOptionWithByteSize(RODeviceFirmwareVersion, VersionMaxSize*C.sizeof_uint)
+	if err != nil {
+		return VersionNumber{}, err
+	}
+
+	val, err := RODeviceFirmwareVersion.Decode(data)
+	if err != nil {
+		return VersionNumber{}, err
+	}
+
+	return newVersionNumber(val.([]uint32)), nil
+}
+
+// CheckCompat checks if the versions of the linked NCSDK API library and of the firmware
+// running on device d are supported by this package. If d is nil only the NCSDK API version is checked.
+// The device must be opened before its firmware version can be checked.
+// It returns *CompatError if any of the versions is not supported or error if it fails to query the versions.
+func CheckCompat(d *Device) error {
+	api, err := APIVersion()
+	if err != nil {
+		return err
+	}
+
+	if api.Major() != SupportedAPIMajor {
+		return &CompatError{Component: "NCSDK API", Version: api, Want: SupportedAPIMajor}
+	}
+
+	if d == nil {
+		return nil
+	}
+
+	fw, err := firmwareVersion(d)
+	if err != nil {
+		return err
+	}
+
+	if fw.Major() != SupportedFirmwareMajor {
+		return &CompatError{Component: "device firmware", Version: fw, Want: SupportedFirmwareMajor}
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+//go:build !ncsdevice
+
+package ncs
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// closedErr returns true if err is one of the errors returned when using a handle which is being torn down
+func closedErr(err error) bool {
+	var stateErr *StateError
+
+	return errors.Is(err, ErrClosed) || errors.Is(err, ErrInvalidHandle) || errors.As(err, &stateErr)
+}
+
+// concurrently runs fn in n goroutines until stop is closed and waits for them to return once it is.
+// fn returns false to stop its goroutine early.
+func concurrently(n int, stop <-chan struct{}, fn func(i int) bool) *sync.WaitGroup {
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				if !fn(i) {
+					return
+				}
+			}
+		}(i)
+	}
+
+	return &wg
+}
+
+func TestConcurrentInferGetOptionClose(t *testing.T) {
+	d := openFakeDevice(t)
+	g, q := allocateFakeGraph(t, d)
+
+	stop := make(chan struct{})
+
+	infers := concurrently(4, stop, func(i int) bool {
+		res, err := Infer(g, q, fakeInput, i)
+		if err != nil {
+			if !closedErr(err) {
+				t.Errorf("unexpected inference error: %v", err)
+			}
+			return false
+		}
+		defer res.Release()
+
+		if !bytes.Equal(res.Data, fakeInput) {
+			t.Errorf("expected output %v, got: %v", fakeInput, res.Data)
+		}
+
+		return true
+	})
+
+	monitors := concurrently(4, stop, func(i int) bool {
+		var err error
+
+		switch i % 4 {
+		case 0:
+			_, err = d.ThermalStats()
+		case 1:
+			_, err = g.Info()
+		case 2:
+			_, err = q.In.Info()
+		case 3:
+			_, err = GetOption[uint](q.Out, ROFifoReadFillLevel)
+		}
+
+		if err != nil && !closedErr(err) {
+			t.Errorf("unexpected monitoring error: %v", err)
+		}
+
+		return true
+	})
+
+	time.Sleep(50 * time.Millisecond)
+
+	// tear the handles down while they're in use
+	if err := q.Close(); err != nil {
+		t.Errorf("failed to close FIFO queue: %v", err)
+	}
+
+	if err := g.Destroy(); err != nil {
+		t.Errorf("failed to destroy graph: %v", err)
+	}
+
+	if err := d.Close(); err != nil {
+		t.Errorf("failed to close device: %v", err)
+	}
+
+	close(stop)
+	infers.Wait()
+	monitors.Wait()
+}
+
+func TestConcurrentGetOptionDestroy(t *testing.T) {
+	d := openFakeDevice(t)
+	g, q := allocateFakeGraph(t, d)
+
+	stop := make(chan struct{})
+
+	getters := concurrently(8, stop, func(i int) bool {
+		var err error
+
+		switch i % 4 {
+		case 0:
+			_, err = d.GetOption(RODeviceState)
+		case 1:
+			_, err = g.GetOption(ROGraphState)
+		case 2:
+			_, err = q.In.GetOption(ROFifoCapacity)
+		case 3:
+			_, err = q.Out.GetOption(ROFifoGraphTensorDesc)
+		}
+
+		if err != nil && !closedErr(err) {
+			t.Errorf("unexpected option error: %v", err)
+		}
+
+		return true
+	})
+
+	time.Sleep(20 * time.Millisecond)
+
+	for _, destroy := range []func() error{q.In.Destroy, q.Out.Destroy, g.Destroy, d.Close, d.Destroy} {
+		if err := destroy(); err != nil {
+			t.Errorf("failed to tear down handle: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	close(stop)
+	getters.Wait()
+}
+
+func TestConcurrentQueueInferenceReadElem(t *testing.T) {
+	d := openFakeDevice(t)
+	g, q := allocateFakeGraph(t, d)
+
+	const workers, elems = 4, 50
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := make(map[int]int)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < elems; i++ {
+				if err := g.QueueInferenceWithFifoElem(q, fakeInput, w*elems+i); err != nil {
+					t.Errorf("failed to queue inference: %v", err)
+					return
+				}
+			}
+		}(w)
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < elems; i++ {
+				elem, err := q.Out.ReadElem()
+				if err != nil {
+					t.Errorf("failed to read element: %v", err)
+					return
+				}
+
+				if !bytes.Equal(elem.Data, fakeInput) {
+					t.Errorf("expected output %v, got: %v", fakeInput, elem.Data)
+				}
+
+				mu.Lock()
+				seen[elem.MetaData.(int)]++
+				mu.Unlock()
+				elem.Release()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(seen) != workers*elems {
+		t.Errorf("expected %d distinct elements, got: %d", workers*elems, len(seen))
+	}
+
+	for meta, n := range seen {
+		if n != 1 {
+			t.Errorf("element %d read %d times", meta, n)
+		}
+	}
+}
+
+func TestConcurrentSyncReadElemClose(t *testing.T) {
+	d := openFakeDevice(t)
+	g, q := allocateFakeGraph(t, d)
+
+	if err := g.QueueInferenceWithFifoElem(q, fakeInput, nil); err != nil {
+		t.Fatalf("failed to queue inference: %v", err)
+	}
+
+	// without a timeout or a cancellable context the read runs synchronously
+	t.Setenv("MVNC_FAKE_READ_DELAY_MS", "200")
+
+	read := make(chan time.Time, 1)
+	go func() {
+		if _, err := q.Out.ReadElem(); err != nil {
+			t.Errorf("failed to read element: %v", err)
+		}
+		read <- time.Now()
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("failed to close device: %v", err)
+	}
+	closed := time.Now()
+
+	if readAt := <-read; closed.Before(readAt) {
+		t.Errorf("expected device close to wait for the synchronous read, closed %s before the read returned", readAt.Sub(closed))
+	}
+}
@@ -0,0 +1,113 @@
+package ncs
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// encryptedGraphMagic prefixes encrypted graph blobs
+const encryptedGraphMagic = "NCSGCM1\x00"
+
+// KeyFunc returns the key used to encrypt and decrypt graph blobs. It allows the key to be fetched
+// from a key management service at the time the graph is decrypted rather than being stored along with it.
+// The key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+type KeyFunc func() ([]byte, error)
+
+// EnvKey returns KeyFunc which reads base64 encoded key from the environment variable name
+func EnvKey(name string) KeyFunc {
+	return func() ([]byte, error) {
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("Graph key environment variable %s not set: %w", name, ErrInvalidParameters)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid graph key in %s: %v: %w", name, err, ErrInvalidParameters)
+		}
+
+		return key, nil
+	}
+}
+
+// IsEncryptedGraph returns true if data is a graph blob encrypted by EncryptGraph
+func IsEncryptedGraph(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedGraphMagic))
+}
+
+// newGCM returns AES-GCM cipher with the key returned by key
+func newGCM(key KeyFunc) (cipher.AEAD, error) {
+	if key == nil {
+		return nil, fmt.Errorf("Missing graph key: %w", ErrInvalidParameters)
+	}
+
+	k, err := key()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid graph key: %v: %w", err, ErrInvalidParameters)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// EncryptGraph encrypts graph blob data with AES-GCM using the key returned by key.
+// The encrypted blob can be distributed in place of the compiled graph file and decrypted
+// in memory with DecryptGraph just before the graph is allocated.
+// It returns error if it fails to get the key or if the key is invalid.
+func EncryptGraph(data []byte, key KeyFunc) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(encryptedGraphMagic)+len(nonce)+len(data)+gcm.Overhead())
+	out = append(out, encryptedGraphMagic...)
+	out = append(out, nonce...)
+
+	// the magic header is authenticated along with the graph data
+	return gcm.Seal(out, nonce, data, []byte(encryptedGraphMagic)), nil
+}
+
+// DecryptGraph decrypts graph blob data encrypted by EncryptGraph using the key returned by key.
+// The decrypted graph is only kept in memory and can be passed to Graph Allocate.
+// It returns error if data is not an encrypted graph, if it fails to get the key
+// or if the data can not be authenticated, i.e. it has been tampered with or the key is wrong.
+func DecryptGraph(data []byte, key KeyFunc) ([]byte, error) {
+	if !IsEncryptedGraph(data) {
+		return nil, fmt.Errorf("Graph blob is not encrypted: %w", ErrInvalidParameters)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[len(encryptedGraphMagic):]
+	if len(data) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, fmt.Errorf("Encrypted graph blob too short: %w", ErrUnsupportedGraphFile)
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+
+	graph, err := gcm.Open(nil, nonce, ciphertext, []byte(encryptedGraphMagic))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decrypt graph blob: %v: %w", err, ErrUnsupportedGraphFile)
+	}
+
+	return graph, nil
+}
@@ -7,8 +7,13 @@ package ncs
 import "C"
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -32,6 +37,11 @@ func (hw DeviceHWVersion) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler interface.
+func (hw DeviceHWVersion) MarshalText() ([]byte, error) {
+	return []byte(hw.String()), nil
+}
+
 // DeviceThermalThrottle defines thermal throttle level
 type DeviceThermalThrottle int
 
@@ -60,6 +70,11 @@ func (dt DeviceThermalThrottle) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler interface.
+func (dt DeviceThermalThrottle) MarshalText() ([]byte, error) {
+	return []byte(dt.String()), nil
+}
+
 // DeviceOption defines NCS device options.
 // The options starting with RW are both gettable and settable.
 // The options starting with RO are only gettable.
@@ -95,7 +110,7 @@ const (
 	RODeviceMVTensorVersion
 	// RODeviceName queries the internal name of the device.
 	RODeviceName
-	// RODeviceMaxExecutors is reserved for future use.
+	// RODeviceMaxExecutors queries the maximum number of executors of the device, if the firmware supports it.
 	RODeviceMaxExecutors
 	// RODeviceHWVersion queries the hardware version of the device.
 	RODeviceHWVersion
@@ -161,6 +176,11 @@ func (do DeviceOption) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler interface.
+func (do DeviceOption) MarshalText() ([]byte, error) {
+	return []byte(do.String()), nil
+}
+
 // Value returns option value as integer
 func (do DeviceOption) Value() int {
 	return int(do)
@@ -168,9 +188,9 @@ func (do DeviceOption) Value() int {
 
 // Decode decodes options data encoded in raw bytes and returns it in its native type.
 // The returned data can be asserted into its native type.
-// If the data contains more than one element you need to specify the number of expected elements via count.
-// It returns error if the data fails to be decoded into the option native type.
-func (do DeviceOption) Decode(data []byte, count int) (interface{}, error) {
+// The number of elements of array options is derived from the length of the data.
+// It returns error if the data is too short or if it fails to be decoded into the option native type.
+func (do DeviceOption) Decode(data []byte) (interface{}, error) {
 	buf := bytes.NewReader(data)
 
 	switch do {
@@ -186,6 +206,10 @@ func (do DeviceOption) Decode(data []byte, count int) (interface{}, error) {
 		RODeviceMaxExecutors,
 		RODeviceHWVersion:
 
+		if err := checkDataLen(do, data, C.sizeof_int, 1); err != nil {
+			return nil, err
+		}
+
 		var val uint32
 		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
 			return nil, err
@@ -196,6 +220,10 @@ func (do DeviceOption) Decode(data []byte, count int) (interface{}, error) {
 
 	case RODeviceThermalStats:
 
+		if err := checkDataLen(do, data, C.sizeof_float, ThermalBufferSize); err != nil {
+			return nil, err
+		}
+
 		var val [ThermalBufferSize]float32
 		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
 			return nil, err
@@ -205,6 +233,10 @@ func (do DeviceOption) Decode(data []byte, count int) (interface{}, error) {
 
 	case RODeviceFirmwareVersion:
 
+		if err := checkDataLen(do, data, C.sizeof_uint, VersionMaxSize); err != nil {
+			return nil, err
+		}
+
 		var val [VersionMaxSize]uint32
 		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
 			return nil, err
@@ -214,6 +246,10 @@ func (do DeviceOption) Decode(data []byte, count int) (interface{}, error) {
 
 	case RODeviceMVTensorVersion:
 
+		if err := checkDataLen(do, data, C.sizeof_uint, 2); err != nil {
+			return nil, err
+		}
+
 		var val [2]uint32
 		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
 			return nil, err
@@ -224,10 +260,10 @@ func (do DeviceOption) Decode(data []byte, count int) (interface{}, error) {
 	case RODeviceDebugInfo,
 		RODeviceName:
 
-		return string(data), nil
+		return decodeString(data), nil
 
 	default:
-		return nil, fmt.Errorf("Unable to decode device option data: %s", do)
+		return nil, fmt.Errorf("Unable to decode device option data: %s: %w", do, ErrInvalidParameters)
 	}
 }
 
@@ -257,25 +293,102 @@ func (ds DeviceState) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler interface.
+func (ds DeviceState) MarshalText() ([]byte, error) {
+	return []byte(ds.String()), nil
+}
+
 // Device is Neural Compute Stick (NCS) device
 type Device struct {
-	handle unsafe.Pointer
+	// mu guards the handle and device state
+	mu      sync.RWMutex
+	handle  unsafe.Pointer
+	state   DeviceState
+	gen     uint64
+	index   int
+	retry   *RetryPolicy
+	timeout time.Duration
+	logger  Logger
+	// calls pins the device while its calls or the calls of its graphs and FIFOs run in the background
+	calls pin
 }
 
-// NewDevice creates new NCS device handle and returns it.
+// NewDevice creates new NCS device handle for the device with the given index and returns it.
+// The handle can be configured via opts, which can also select the device by WithIndex or WithSerial.
+// It returns error if any of opts is not supported by devices or if it fails to create the device handle.
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncDeviceCreate.html
-func NewDevice(index int) (*Device, error) {
+func NewDevice(index int, opts ...Opt) (*Device, error) {
+	o, err := newHandleOpts("device", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case o.set&optIndex != 0:
+		index = o.index
+	case o.set&optSerial != 0:
+		if index, err = serialDeviceIndex(o.serial); err != nil {
+			return nil, err
+		}
+	}
+
 	var handle unsafe.Pointer
 
 	s := C.ncs_DeviceCreate(C.int(index), &handle)
 
 	if Status(s) != StatusOK {
-		return nil, fmt.Errorf("Failed to create new device: %s", Status(s))
+		err := newError("Create", "device", s)
+		err.Index = index
+		return nil, err
+	}
+
+	d := &Device{handle: handle, index: index, retry: o.retry, timeout: o.timeout, logger: o.logger}
+	trackHandle(d, "device", "")
+	logf(d.logger, "device %d: created", index)
+
+	return d, nil
+}
+
+// generation returns the device generation which changes every time the device is closed or destroyed
+func (d *Device) generation() uint64 {
+	return atomic.LoadUint64(&d.gen)
+}
+
+// devicePin returns the pin of device d or nil if d is nil
+func devicePin(d *Device) *pin {
+	if d == nil {
+		return nil
+	}
+
+	return &d.calls
+}
+
+// newError returns new Error for the failed device operation op.
+// It fetches the device debug information if the operation failed with StatusMyriadError.
+// It must be called with d.mu held.
+func (d *Device) newError(op string, s C.int) *Error {
+	err := newError(op, "device", s)
+	err.Index = d.index
+
+	if err.Status == StatusMyriadError {
+		err.DebugInfo = debugInfo("device", d.handle, RODeviceDebugInfo)
 	}
 
-	return &Device{handle: handle}, nil
+	return err
+}
+
+// debugInfo returns the device debug information or empty string if it can't be queried.
+// It does not block if the device is being closed or destroyed, so it is safe to call
+// while the device is read locked by the caller.
+func (d *Device) debugInfo() string {
+	if !d.mu.TryRLock() {
+		return ""
+	}
+	defer d.mu.RUnlock()
+
+	return debugInfo("device", d.handle, RODeviceDebugInfo)
 }
 
 // Open initializes NCS device and opens device communication channel.
@@ -284,35 +397,119 @@ func NewDevice(index int) (*Device, error) {
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncDeviceOpen.html
 func (d *Device) Open() error {
-	s := C.ncs_DeviceOpen(d.handle)
+	return d.OpenContext(context.Background())
+}
+
+// OpenContext initializes NCS device and opens device communication channel.
+// It returns ctx error if ctx is done before the device is opened. See Open for more details.
+func (d *Device) OpenContext(ctx context.Context) error {
+	if d == nil {
+		return invalidHandle("Open", "device")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.handle == nil {
+		return ErrClosed
+	}
+
+	if d.state == DeviceOpened {
+		return &StateError{Op: "Open", Resource: "device", State: d.state}
+	}
+
+	handle := d.handle
+	s, err := d.retry.do(ctx, func() (C.int, error) {
+		return monitor(ctx, callTimeout(d.timeout), func() C.int {
+			return C.ncs_DeviceOpen(handle)
+		}, &d.calls)
+	})
+
+	if err != nil {
+		return err
+	}
 
 	if Status(s) != StatusOK {
-		return fmt.Errorf("Failed to open device: %s", Status(s))
+		return d.newError("Open", s)
 	}
 
+	d.state = DeviceOpened
+	logf(d.logger, "device %d: opened", d.index)
+
 	return nil
 }
 
+// SetTimeout sets the timeout of the potentially blocking device calls.
+// Zero duration means the package default timeout is used. See SetDefaultTimeout for more details.
+func (d *Device) SetTimeout(t time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.timeout = t
+}
+
+// SetRetryPolicy sets the policy used to retry the device calls which fail with StatusBusy or StatusTimeout.
+// Passing nil disables retries.
+func (d *Device) SetRetryPolicy(p *RetryPolicy) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.retry = p
+}
+
+// Handle returns the underlying NCSDK struct ncDeviceHandle_t pointer or nil if the device has been destroyed.
+//
+// This is an escape hatch for advanced use: it allows calling NCSDK functions which are not wrapped
+// by this package. The returned pointer must not be used after the device has been destroyed and
+// it must not be passed to ncDeviceClose or ncDeviceDestroy; use the Device methods instead.
+// Changes made to the device through the returned pointer are not tracked by the Device.
+func (d *Device) Handle() unsafe.Pointer {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.handle
+}
+
 // GetOption queries the value of an option for the device and returns it encoded in a byte slice.
 // It returns error if it fails to retrieve the option value.
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncDeviceGetOption.html
 func (d *Device) GetOption(opt DeviceOption) ([]byte, error) {
-	if opt == RODeviceMaxExecutors || opt == RODeviceDebugInfo {
-		return nil, fmt.Errorf("Option %s not implemented", opt)
+	if d == nil {
+		return nil, invalidHandle("GetOption", "device")
 	}
 
-	var data unsafe.Pointer
-	var dataLen C.uint
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 
-	s := C.ncs_DeviceGetOption(d.handle, C.int(opt), data, &dataLen)
+	if d.handle == nil {
+		return nil, ErrClosed
+	}
 
-	if Status(s) == StatusInvalidDataLength {
-		return d.GetOptionWithByteSize(opt, deviceOptSize[opt]*uint(dataLen))
+	size := deviceOptSize[opt]
+	if opt == RODeviceDebugInfo {
+		size = DebugBufferSize
 	}
 
-	return nil, fmt.Errorf("Failed to read %s option: %s", opt, Status(s))
+	data, s, err := queryOption("device", d.handle, opt, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if Status(s) == StatusUnsupportedFeature {
+		return nil, fmt.Errorf("Option %s: %w", opt, ErrUnsupportedOption)
+	}
+
+	if Status(s) != StatusOK {
+		return nil, d.newError("GetOption", s)
+	}
+
+	return data, nil
 }
 
 // GetOptionsWithSize queries NCS device options and returns it encoded in a byte slice of size elements.
@@ -322,39 +519,360 @@ func (d *Device) GetOption(opt DeviceOption) ([]byte, error) {
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncDeviceGetOption.html
 func (d *Device) GetOptionWithByteSize(opt DeviceOption, size uint) ([]byte, error) {
-	if opt == RODeviceMaxExecutors || opt == RODeviceDebugInfo {
-		return nil, fmt.Errorf("Option %s not implemented", opt)
+	if d == nil {
+		return nil, invalidHandle("GetOption", "device")
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.handle == nil {
+		return nil, ErrClosed
+	}
+
+	data, err := getOption("device", d.handle, opt, size)
+	if errors.Is(err, ErrUnsupportedFeature) {
+		return nil, fmt.Errorf("Option %s: %w", opt, ErrUnsupportedOption)
 	}
 
-	return getOption("device", d.handle, opt, size)
+	return data, err
+}
+
+// ThermalStats returns the maximum temperatures of the device for the last ThermalBufferSize seconds.
+// It returns error if it fails to query the option.
+func (d *Device) ThermalStats() ([]float32, error) {
+	data, err := d.GetOptionWithByteSize(RODeviceThermalStats, ThermalBufferSize*C.sizeof_float)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := RODeviceThermalStats.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return val.([]float32), nil
+}
+
+// ThermalThrottle returns the current thermal throttle level of the device.
+// It returns error if it fails to query the option.
+func (d *Device) ThermalThrottle() (DeviceThermalThrottle, error) {
+	val, err := d.uintOption(RODeviceThermalThrottle)
+
+	return DeviceThermalThrottle(val), err
+}
+
+// State returns the state of the device as reported by NCSDK.
+// It returns error if it fails to query the option.
+func (d *Device) State() (DeviceState, error) {
+	val, err := d.uintOption(RODeviceState)
+
+	return DeviceState(val), err
+}
+
+// MemoryUsed returns the amount of device memory in use in bytes.
+// It returns error if it fails to query the option.
+func (d *Device) MemoryUsed() (uint, error) {
+	return d.uintOption(RODeviceMemoryUsed)
+}
+
+// MemorySize returns the total amount of device memory in bytes.
+// It returns error if it fails to query the option.
+func (d *Device) MemorySize() (uint, error) {
+	return d.uintOption(RODeviceMemorySize)
+}
+
+// HWVersion returns the hardware version of the device.
+// It returns error if it fails to query the option.
+func (d *Device) HWVersion() (DeviceHWVersion, error) {
+	val, err := d.uintOption(RODeviceHWVersion)
+
+	return DeviceHWVersion(val), err
+}
+
+// FirmwareVersion returns the version of the firmware running on the device.
+// The device must be opened before its firmware version can be queried.
+// It returns error if it fails to query the option.
+func (d *Device) FirmwareVersion() (VersionNumber, error) {
+	return firmwareVersion(d)
+}
+
+// DebugInfo returns the debug information of the device, which provides more details
+// when the result of a device operation was StatusMyriadError.
+// It returns error if it fails to query the option.
+func (d *Device) DebugInfo() (string, error) {
+	data, err := d.GetOptionWithByteSize(RODeviceDebugInfo, DebugBufferSize)
+	if err != nil {
+		return "", err
+	}
+
+	return decodeString(data), nil
+}
+
+// MaxExecutors returns the maximum number of executors of the device.
+// It returns ErrUnsupportedOption if the option is not supported by the device firmware.
+func (d *Device) MaxExecutors() (uint, error) {
+	return d.uintOption(RODeviceMaxExecutors)
+}
+
+// DeviceInfo aggregates the information about NCS device
+type DeviceInfo struct {
+	// Index is the device index
+	Index int `json:"index"`
+	// Name is the internal name of the device
+	Name string `json:"name"`
+	// HWVersion is the device hardware version
+	HWVersion DeviceHWVersion `json:"hw_version"`
+	// FirmwareVersion is the version of the firmware running on the device
+	FirmwareVersion VersionNumber `json:"firmware_version"`
+	// MVTensorVersion is the version of the mvtensor library linked with the API
+	MVTensorVersion VersionNumber `json:"mvtensor_version"`
+	// MemorySize is the total amount of device memory in bytes
+	MemorySize uint `json:"memory_size"`
+	// MemoryUsed is the amount of device memory in use in bytes
+	MemoryUsed uint `json:"memory_used"`
+	// MaxGraphs is the maximum number of graphs which can be allocated on the device
+	MaxGraphs uint `json:"max_graphs"`
+	// MaxFifos is the maximum number of FIFOs which can be allocated on the device
+	MaxFifos uint `json:"max_fifos"`
+	// MaxExecutors is the maximum number of executors of the device. It is zero if the firmware does not report it.
+	MaxExecutors uint `json:"max_executors,omitempty"`
+	// State is the device state as reported by NCSDK
+	State DeviceState `json:"state"`
+}
+
+// Info queries all the device information options at once and returns them aggregated in DeviceInfo.
+// The device must be opened before its information can be queried. The fields whose options are not
+// supported by the device firmware are left unset.
+// It returns error if it fails to query any of the supported options.
+func (d *Device) Info() (*DeviceInfo, error) {
+	if d == nil {
+		return nil, invalidHandle("GetOption", "device")
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.handle == nil {
+		return nil, ErrClosed
+	}
+
+	info := &DeviceInfo{Index: d.index}
+
+	fields := []struct {
+		opt  DeviceOption
+		size uint
+		set  func(val interface{})
+	}{
+		{RODeviceName, deviceOptSize[RODeviceName], func(val interface{}) { info.Name = val.(string) }},
+		{RODeviceHWVersion, deviceOptSize[RODeviceHWVersion], func(val interface{}) { info.HWVersion = DeviceHWVersion(val.(uint)) }},
+		{RODeviceFirmwareVersion, VersionMaxSize * C.sizeof_uint, func(val interface{}) { info.FirmwareVersion = newVersionNumber(val.([]uint32)) }},
+		{RODeviceMVTensorVersion, 2 * C.sizeof_uint, func(val interface{}) { info.MVTensorVersion = newVersionNumber(val.([]uint32)) }},
+		{RODeviceMemorySize, deviceOptSize[RODeviceMemorySize], func(val interface{}) { info.MemorySize = val.(uint) }},
+		{RODeviceMemoryUsed, deviceOptSize[RODeviceMemoryUsed], func(val interface{}) { info.MemoryUsed = val.(uint) }},
+		{RODeviceMaxGraphCount, deviceOptSize[RODeviceMaxGraphCount], func(val interface{}) { info.MaxGraphs = val.(uint) }},
+		{RODeviceMaxFifoCount, deviceOptSize[RODeviceMaxFifoCount], func(val interface{}) { info.MaxFifos = val.(uint) }},
+		{RODeviceMaxExecutors, deviceOptSize[RODeviceMaxExecutors], func(val interface{}) { info.MaxExecutors = val.(uint) }},
+		{RODeviceState, deviceOptSize[RODeviceState], func(val interface{}) { info.State = DeviceState(val.(uint)) }},
+	}
+
+	for _, f := range fields {
+		data, s, err := queryOption("device", d.handle, f.opt, f.size)
+		if err != nil {
+			return nil, err
+		}
+
+		if Status(s) == StatusUnsupportedFeature {
+			continue
+		}
+
+		if Status(s) != StatusOK {
+			return nil, d.newError("GetOption", s)
+		}
+
+		val, err := f.opt.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+		f.set(val)
+	}
+
+	return info, nil
+}
+
+// uintOption queries device option opt which is decoded into uint
+func (d *Device) uintOption(opt DeviceOption) (uint, error) {
+	data, err := d.GetOptionWithByteSize(opt, deviceOptSize[opt])
+	if err != nil {
+		return 0, err
+	}
+
+	val, err := opt.Decode(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return val.(uint), nil
 }
 
 // Close closes the communication channel with NCS device.
 // It returns error if it fails to close the communication channel.
+// It returns ErrClosed if the device has already been closed or destroyed.
+// Close implements io.Closer interface. Note that it does not destroy the device handle.
+// It blocks until the abandoned calls of the device and of its graphs and FIFOs have returned.
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncDeviceClose.html
 func (d *Device) Close() error {
-	s := C.ncs_DeviceClose(d.handle)
+	return d.CloseContext(context.Background())
+}
+
+// CloseContext closes the communication channel with NCS device.
+// It returns ctx error if ctx is done before the device is closed. See Close for more details.
+func (d *Device) CloseContext(ctx context.Context) error {
+	if d == nil {
+		return invalidHandle("Close", "device")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.handle == nil || d.state == DeviceClosed {
+		return ErrClosed
+	}
+
+	if d.state != DeviceOpened {
+		return &StateError{Op: "Close", Resource: "device", State: d.state}
+	}
+
+	if err := d.calls.wait(ctx); err != nil {
+		return err
+	}
+
+	handle := d.handle
+	s, err := monitor(ctx, 0, func() C.int {
+		return C.ncs_DeviceClose(handle)
+	}, &d.calls)
+
+	if err != nil {
+		return err
+	}
 
 	if Status(s) != StatusOK {
-		return fmt.Errorf("Failed to close device: %s", Status(s))
+		return d.newError("Close", s)
 	}
 
+	d.state = DeviceClosed
+	logf(d.logger, "device %d: closed", d.index)
+	atomic.AddUint64(&d.gen, 1)
+	// the elements left in the device FIFOs are lost along with their metadata
+	releaseDeviceMetaTokens(d)
+
 	return nil
 }
 
 // Destroy destroys NCS device handle and frees associated resources.
 // This function must be called for every device that was initialized with NewDevice().
+// It returns ErrClosed if the device has already been destroyed.
+// It blocks until the abandoned calls of the device and of its graphs and FIFOs have returned.
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncDeviceDestroy.html
 func (d *Device) Destroy() error {
+	if d == nil {
+		return invalidHandle("Destroy", "device")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.handle == nil {
+		return ErrClosed
+	}
+
+	// the background calls must return before the handle is freed
+	d.calls.wait(context.Background())
+
 	s := C.ncs_DeviceDestroy(&d.handle)
 
 	if Status(s) != StatusOK {
-		return fmt.Errorf("Failed to destroy device: %s", Status(s))
+		return d.newError("Destroy", s)
 	}
 
+	d.handle = nil
+	atomic.AddUint64(&d.gen, 1)
+	releaseDeviceMetaTokens(d)
+	untrackHandle(d)
+	logf(d.logger, "device %d: destroyed", d.index)
+
 	return nil
 }
+
+// maxDevices is the maximum number of devices Devices looks for
+const maxDevices = 64
+
+// DeviceDesc is a lightweight descriptor of NCS device attached to the host.
+// It does not hold any device handle, so it does not need to be released.
+type DeviceDesc struct {
+	// Index is the index of the device
+	Index int
+	// Name is the internal name of the device, it is empty if NCSDK does not support querying it
+	Name string
+}
+
+// Open creates new device handle for the described device and opens it.
+// The handle can be configured via opts. It must be destroyed once it's no longer needed.
+// It returns error if it fails to create or open the device.
+func (dd DeviceDesc) Open(opts ...Opt) (*Device, error) {
+	d, err := NewDevice(dd.Index, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Open(); err != nil {
+		d.Destroy()
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Devices returns descriptors of all NCS devices attached to the host ordered by their index.
+// The devices are not opened, so they can be opened individually e.g.:
+//
+//	devs, err := ncs.Devices()
+//	for _, dd := range devs {
+//		d, err := dd.Open()
+//		...
+//	}
+//
+// The device names are queried without opening the devices, as NCSDK names them after the USB port they're attached to.
+// It stops at the first index NCSDK reports no device for. It returns error if it fails to create any of the device
+// handles or to query any of the device names, unless querying the name is not supported.
+func Devices() ([]DeviceDesc, error) {
+	var devices []DeviceDesc
+
+	for i := 0; i < maxDevices; i++ {
+		d, err := NewDevice(i)
+		if err != nil {
+			if errors.Is(err, ErrDeviceNotFound) {
+				break
+			}
+			return nil, err
+		}
+
+		name, err := GetOption[string](d, RODeviceName)
+		if err != nil && !errors.Is(err, ErrUnsupportedOption) {
+			d.Destroy()
+			return nil, err
+		}
+
+		if err := d.Destroy(); err != nil {
+			return nil, err
+		}
+
+		devices = append(devices, DeviceDesc{Index: i, Name: name})
+	}
+
+	return devices, nil
+}
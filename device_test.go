@@ -0,0 +1,82 @@
+package ncs
+
+import "testing"
+
+func TestDeviceOptionDecode(t *testing.T) {
+	scalar := []DeviceOption{
+		RODeviceThermalThrottle,
+		RODeviceState,
+		RODeviceMemoryUsed,
+		RODeviceMemorySize,
+		RODeviceMaxFifoCount,
+		RODeviceAllocatedFifoCount,
+		RODeviceMaxGraphCount,
+		RODeviceAllocatedGraphCount,
+		RODeviceClassLimit,
+		RODeviceMaxExecutors,
+		RODeviceHWVersion,
+	}
+
+	for _, opt := range scalar {
+		t.Run(opt.String(), func(t *testing.T) {
+			testDecode(t, opt, scalarDecodeTests(t))
+		})
+	}
+
+	for _, opt := range []DeviceOption{RODeviceDebugInfo, RODeviceName} {
+		t.Run(opt.String(), func(t *testing.T) {
+			testDecode(t, opt, stringDecodeTests())
+		})
+	}
+
+	thermal := make([]float32, 2*ThermalBufferSize)
+	for i := range thermal {
+		thermal[i] = float32(i) + 0.5
+	}
+
+	t.Run(RODeviceThermalStats.String(), func(t *testing.T) {
+		testDecode(t, RODeviceThermalStats, []decodeTest{
+			{name: "Empty", data: nil, err: ErrInvalidDataLength},
+			{name: "Short", data: encode(t, thermal[:ThermalBufferSize-1]), err: ErrInvalidDataLength},
+			{name: "Exact", data: encode(t, thermal[:ThermalBufferSize]), want: thermal[:ThermalBufferSize]},
+			{name: "Multi", data: encode(t, thermal), want: thermal[:ThermalBufferSize]},
+		})
+	})
+
+	t.Run(RODeviceFirmwareVersion.String(), func(t *testing.T) {
+		testDecode(t, RODeviceFirmwareVersion, []decodeTest{
+			{name: "Empty", data: nil, err: ErrInvalidDataLength},
+			{name: "Short", data: encode(t, seq(VersionMaxSize-1)), err: ErrInvalidDataLength},
+			{name: "Exact", data: encode(t, seq(VersionMaxSize)), want: seq(VersionMaxSize)},
+			{name: "Multi", data: encode(t, seq(2*VersionMaxSize)), want: seq(VersionMaxSize)},
+		})
+	})
+
+	t.Run(RODeviceMVTensorVersion.String(), func(t *testing.T) {
+		testDecode(t, RODeviceMVTensorVersion, []decodeTest{
+			{name: "Empty", data: nil, err: ErrInvalidDataLength},
+			{name: "Short", data: encode(t, seq(1)), err: ErrInvalidDataLength},
+			{name: "Exact", data: encode(t, seq(2)), want: seq(2)},
+			{name: "Multi", data: encode(t, seq(4)), want: seq(2)},
+		})
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		testDecode(t, DeviceOption(-1), []decodeTest{
+			{name: "Exact", data: encode(t, uint32(1)), err: ErrInvalidParameters},
+		})
+	})
+}
+
+func FuzzDeviceOptionDecode(f *testing.F) {
+	for opt := RODeviceThermalStats; opt <= RODeviceHWVersion; opt++ {
+		f.Add(int(opt), []byte(nil))
+		f.Add(int(opt), make([]byte, int32Size))
+		f.Add(int(opt), make([]byte, ThermalBufferSize*int32Size))
+	}
+
+	f.Fuzz(func(t *testing.T, opt int, data []byte) {
+		// any error is fine as long as decoding malformed data never panics
+		_, _ = DeviceOption(opt).Decode(data)
+	})
+}
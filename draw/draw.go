@@ -0,0 +1,144 @@
+// Package draw renders the bounding boxes and labels of detected objects onto images.
+// It only depends on the standard library image packages and golang.org/x/image/font,
+// so annotated images can be produced without OpenCV.
+package draw
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/milosgajdos/ncs/results"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Opts configures drawing of detections
+type Opts struct {
+	// Color is the color of the boxes and of the label backgrounds. It defaults to green.
+	Color color.Color
+	// TextColor is the color of the label text. It defaults to black.
+	TextColor color.Color
+	// Thickness is the box line thickness in pixels. It defaults to 2.
+	Thickness int
+	// Face is the label font face. It defaults to basicfont.Face7x13.
+	Face font.Face
+	// HideScore hides the detection score in the labels
+	HideScore bool
+}
+
+// defaults returns a copy of opts with the unset options set to their defaults
+func defaults(opts *Opts) Opts {
+	var o Opts
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Color == nil {
+		o.Color = color.RGBA{G: 0xff, A: 0xff}
+	}
+	if o.TextColor == nil {
+		o.TextColor = color.Black
+	}
+	if o.Thickness <= 0 {
+		o.Thickness = 2
+	}
+	if o.Face == nil {
+		o.Face = basicfont.Face7x13
+	}
+
+	return o
+}
+
+// RGBA returns a copy of img which can be drawn on
+func RGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(b)
+	draw.Draw(dst, b, img, b.Min, draw.Src)
+
+	return dst
+}
+
+// Detections draws the bounding boxes of dets onto img along with their labels and scores.
+// The boxes are scaled to the bounds of img. If opts is nil the default options are used.
+func Detections(img draw.Image, dets results.Detections, opts *Opts) {
+	o := defaults(opts)
+	b := img.Bounds()
+
+	for _, det := range dets {
+		r := det.Box.Rect(b.Dx(), b.Dy()).Add(b.Min)
+		Box(img, r, o.Color, o.Thickness)
+
+		text := det.Label
+		if text == "" {
+			text = fmt.Sprintf("class %d", det.Index)
+		}
+		if !o.HideScore {
+			text = fmt.Sprintf("%s %.2f", text, det.Score)
+		}
+
+		Label(img, r.Min, text, o.TextColor, o.Color, o.Face)
+	}
+}
+
+// Box draws rectangle r with line of the given thickness and color c onto img.
+// The line is drawn inside r, so if thickness is larger than half of r, r is filled.
+func Box(img draw.Image, r image.Rectangle, c color.Color, thickness int) {
+	src := image.NewUniform(c)
+	t := thickness
+
+	// the inner edges of the lines, which are clamped to r so the lines never overlap or leave r
+	top, left := min(r.Min.Y+t, r.Max.Y), min(r.Min.X+t, r.Max.X)
+	bottom, right := max(r.Max.Y-t, top), max(r.Max.X-t, left)
+
+	// image.Rect would swap the coordinates of the empty lines, so the rectangles are built directly
+	lines := []image.Rectangle{
+		{Min: r.Min, Max: image.Pt(r.Max.X, top)},
+		{Min: image.Pt(r.Min.X, bottom), Max: r.Max},
+		{Min: image.Pt(r.Min.X, top), Max: image.Pt(left, bottom)},
+		{Min: image.Pt(right, top), Max: image.Pt(r.Max.X, bottom)},
+	}
+
+	for _, l := range lines {
+		if !l.Empty() {
+			draw.Draw(img, l, src, image.Point{}, draw.Over)
+		}
+	}
+}
+
+// Label draws text with color fg on a background of color bg onto img.
+// The label is placed right above pt, or right below it if there is no room above pt in img.
+// It is shifted left if it would otherwise overflow the right edge of img.
+func Label(img draw.Image, pt image.Point, text string, fg, bg color.Color, face font.Face) {
+	metrics := face.Metrics()
+	ascent, height := metrics.Ascent.Ceil(), metrics.Height.Ceil()
+	width := font.MeasureString(face, text).Ceil()
+	bounds := img.Bounds()
+
+	const pad = 2
+	top := pt.Y - height - 2*pad
+	if top < bounds.Min.Y {
+		top = pt.Y
+	}
+
+	left := pt.X
+	if over := left + width + 2*pad - bounds.Max.X; over > 0 {
+		left -= over
+	}
+	if left < bounds.Min.X {
+		left = bounds.Min.X
+	}
+
+	bgRect := image.Rect(left, top, left+width+2*pad, top+height+2*pad)
+	draw.Draw(img, bgRect, image.NewUniform(bg), image.Point{}, draw.Over)
+
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(fg),
+		Face: face,
+		Dot:  fixed.P(left+pad, top+pad+ascent),
+	}
+	d.DrawString(text)
+}
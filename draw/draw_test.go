@@ -0,0 +1,145 @@
+package draw
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/milosgajdos/ncs/results"
+	"golang.org/x/image/font/basicfont"
+)
+
+var (
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	red   = color.RGBA{R: 0xff, A: 0xff}
+	blue  = color.RGBA{B: 0xff, A: 0xff}
+)
+
+// canvas returns white image of the given size
+func canvas(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	return img
+}
+
+// count returns the number of pixels of img within r which have color c
+func count(img *image.RGBA, r image.Rectangle, c color.RGBA) int {
+	var n int
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.RGBAAt(x, y) == c {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+func TestBox(t *testing.T) {
+	tests := []struct {
+		name      string
+		r         image.Rectangle
+		thickness int
+		want      int
+	}{
+		{name: "Thin", r: image.Rect(2, 2, 8, 7), thickness: 1, want: 6*5 - 4*3},
+		{name: "Thick", r: image.Rect(2, 2, 8, 7), thickness: 2, want: 6*5 - 2*1},
+		{name: "HalfBox", r: image.Rect(2, 2, 8, 8), thickness: 3, want: 6 * 6},
+		{name: "ThickerThanBox", r: image.Rect(2, 2, 8, 7), thickness: 10, want: 6 * 5},
+		{name: "ClippedByImage", r: image.Rect(-2, -2, 4, 4), thickness: 1, want: 4*4 - 3*3},
+		{name: "Empty", r: image.Rect(3, 3, 3, 6), thickness: 2, want: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			img := canvas(10, 10)
+			Box(img, tc.r, red, tc.thickness)
+
+			if n := count(img, img.Bounds(), red); n != tc.want {
+				t.Errorf("expected %d box pixels, got: %d", tc.want, n)
+			}
+
+			if n := count(img, tc.r.Intersect(img.Bounds()), red); n != tc.want {
+				t.Errorf("expected all %d box pixels inside the box, got: %d", tc.want, n)
+			}
+		})
+	}
+}
+
+func TestBoxTranslucent(t *testing.T) {
+	// overlapping lines would blend translucent color twice
+	img := canvas(10, 10)
+	Box(img, image.Rect(0, 0, 6, 6), color.RGBA{R: 0x80, A: 0x80}, 4)
+
+	want := img.RGBAAt(0, 0)
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			if got := img.RGBAAt(x, y); got != want {
+				t.Fatalf("pixel (%d,%d): expected %v, got: %v", x, y, want, got)
+			}
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	face := basicfont.Face7x13
+	// the label background is 3 characters wide and 13 pixels high plus 2 pixels of padding on every side
+	const width, height = 3*7 + 4, 13 + 4
+
+	tests := []struct {
+		name string
+		pt   image.Point
+		want image.Rectangle
+	}{
+		{name: "Above", pt: image.Pt(10, 30), want: image.Rect(10, 30-height, 10+width, 30)},
+		{name: "BelowAtTop", pt: image.Pt(10, 5), want: image.Rect(10, 5, 10+width, 5+height)},
+		{name: "BottomRight", pt: image.Pt(99, 39), want: image.Rect(100-width, 39-height, 100, 39)},
+		{name: "TopRight", pt: image.Pt(90, 0), want: image.Rect(100-width, 0, 100, height)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			img := canvas(100, 40)
+			Label(img, tc.pt, "abc", red, blue, face)
+
+			painted := count(img, img.Bounds(), blue) + count(img, img.Bounds(), red)
+			inside := count(img, tc.want, blue) + count(img, tc.want, red)
+
+			if inside != tc.want.Dx()*tc.want.Dy() {
+				t.Errorf("expected label to fill %v, painted %d of %d pixels", tc.want, inside, tc.want.Dx()*tc.want.Dy())
+			}
+
+			if painted != inside {
+				t.Errorf("expected label within %v, painted %d pixels outside", tc.want, painted-inside)
+			}
+
+			if count(img, tc.want, red) == 0 {
+				t.Errorf("expected label text to be drawn")
+			}
+		})
+	}
+}
+
+func TestDetections(t *testing.T) {
+	img := canvas(100, 100)
+	dets := results.Detections{{Index: 1, Label: "cat", Score: 0.5, Box: results.Box{XMin: 0.5, YMin: 0.5, XMax: 1, YMax: 1}}}
+
+	Detections(img, dets, &Opts{Color: blue, TextColor: red, Thickness: 1, HideScore: true})
+
+	// the box is drawn in the bottom right quarter and the label right above it
+	if n := count(img, image.Rect(50, 50, 100, 100), blue); n != 4*50-4 {
+		t.Errorf("expected %d box pixels, got: %d", 4*50-4, n)
+	}
+
+	if n := count(img, image.Rect(50, 33, 100, 50), red); n == 0 {
+		t.Errorf("expected label text above the box")
+	}
+
+	if n := count(img, image.Rect(0, 0, 100, 33), blue) + count(img, image.Rect(0, 0, 50, 100), blue); n != 0 {
+		t.Errorf("expected nothing drawn outside the box and its label, got %d pixels", n)
+	}
+}
@@ -0,0 +1,162 @@
+package draw
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// ColorMap maps values in [0, 1] to colors
+type ColorMap func(v float32) color.RGBA
+
+// Jet maps values in [0, 1] to the blue-cyan-yellow-red colors of the classic jet color map
+func Jet(v float32) color.RGBA {
+	v = clamp(v)
+
+	channel := func(offset float32) uint8 {
+		c := 1.5 - float32(math.Abs(float64(4*v-offset)))
+		return uint8(255 * clamp(c))
+	}
+
+	return color.RGBA{R: channel(3), G: channel(2), B: channel(1), A: 0xff}
+}
+
+// Gray maps values in [0, 1] to grayscale colors from black to white
+func Gray(v float32) color.RGBA {
+	g := uint8(255 * clamp(v))
+
+	return color.RGBA{R: g, G: g, B: g, A: 0xff}
+}
+
+// clamp clamps v into [0, 1]
+func clamp(v float32) float32 {
+	switch {
+	case v < 0 || v != v:
+		return 0
+	case v > 1:
+		return 1
+	}
+
+	return v
+}
+
+// Heatmap renders values of width x height output tensor stored in row-major order as an image
+// colored by cm. The values are min-max normalized into [0, 1] first. If cm is nil Jet is used.
+// It returns error if the number of values does not match the tensor dimensions.
+func Heatmap(values []float32, width, height int, cm ColorMap) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 || len(values) != width*height {
+		return nil, fmt.Errorf("Invalid heatmap size: %d values for %dx%d map", len(values), width, height)
+	}
+
+	if cm == nil {
+		cm = Jet
+	}
+
+	min, max := float32(math.Inf(1)), float32(math.Inf(-1))
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	scale := float32(0)
+	if max > min {
+		scale = 1 / (max - min)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i, v := range values {
+		img.SetRGBA(i%width, i/width, cm((v-min)*scale))
+	}
+
+	return img, nil
+}
+
+// Segmentation renders width x height segmentation map of class indices stored in row-major order as an image.
+// Class i is colored by palette[i % len(palette)]. If palette is empty a default palette is used.
+// It returns error if the number of classes does not match the map dimensions.
+func Segmentation(classes []int, width, height int, palette []color.RGBA) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 || len(classes) != width*height {
+		return nil, fmt.Errorf("Invalid segmentation map size: %d classes for %dx%d map", len(classes), width, height)
+	}
+
+	if len(palette) == 0 {
+		palette = defaultPalette
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i, c := range classes {
+		if c < 0 {
+			continue
+		}
+		img.SetRGBA(i%width, i/width, palette[c%len(palette)])
+	}
+
+	return img, nil
+}
+
+// Argmax returns the segmentation map of the class indices with the highest scores of width x height x classes
+// output tensor stored in HWC order, as returned by segmentation models.
+// It returns error if the number of scores does not match the tensor dimensions.
+func Argmax(scores []float32, width, height, classes int) ([]int, error) {
+	if classes <= 0 || len(scores) != width*height*classes {
+		return nil, fmt.Errorf("Invalid scores size: %d scores for %dx%dx%d tensor", len(scores), width, height, classes)
+	}
+
+	out := make([]int, width*height)
+	for i := range out {
+		px := scores[i*classes : (i+1)*classes]
+		best := 0
+		for c, v := range px {
+			if v > px[best] {
+				best = c
+			}
+		}
+		out[i] = best
+	}
+
+	return out, nil
+}
+
+// defaultPalette is the palette used to color segmentation maps, the background class 0 is black
+var defaultPalette = []color.RGBA{
+	{0x00, 0x00, 0x00, 0xff},
+	{0xe6, 0x19, 0x4b, 0xff},
+	{0x3c, 0xb4, 0x4b, 0xff},
+	{0xff, 0xe1, 0x19, 0xff},
+	{0x43, 0x63, 0xd8, 0xff},
+	{0xf5, 0x82, 0x31, 0xff},
+	{0x91, 0x1e, 0xb4, 0xff},
+	{0x42, 0xd4, 0xf4, 0xff},
+	{0xf0, 0x32, 0xe6, 0xff},
+	{0xbf, 0xef, 0x45, 0xff},
+	{0xfa, 0xbe, 0xd4, 0xff},
+	{0x46, 0x99, 0x90, 0xff},
+	{0xdc, 0xbe, 0xff, 0xff},
+	{0x9a, 0x63, 0x24, 0xff},
+	{0xff, 0xfa, 0xc8, 0xff},
+	{0x80, 0x00, 0x00, 0xff},
+	{0xaa, 0xff, 0xc3, 0xff},
+	{0x80, 0x80, 0x00, 0xff},
+	{0xff, 0xd8, 0xb1, 0xff},
+	{0x00, 0x00, 0x75, 0xff},
+	{0xa9, 0xa9, 0xa9, 0xff},
+}
+
+// Overlay blends overlay scaled to the bounds of img onto img with the given opacity in [0, 1]
+func Overlay(img draw.Image, overlay image.Image, opacity float64) {
+	mask := image.NewUniform(color.Alpha{A: uint8(255 * math.Max(0, math.Min(1, opacity)))})
+
+	b := img.Bounds()
+	scaled := image.NewRGBA(b)
+	xdraw.BiLinear.Scale(scaled, b, overlay, overlay.Bounds(), xdraw.Src, nil)
+
+	draw.DrawMask(img, b, scaled, b.Min, mask, image.Point{}, draw.Over)
+}
@@ -0,0 +1,165 @@
+package draw
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestJet(t *testing.T) {
+	tests := []struct {
+		name string
+		v    float32
+		want color.RGBA
+	}{
+		{name: "Min", v: 0, want: color.RGBA{B: 127, A: 0xff}},
+		{name: "Mid", v: 0.5, want: color.RGBA{R: 127, G: 255, B: 127, A: 0xff}},
+		{name: "Max", v: 1, want: color.RGBA{R: 127, A: 0xff}},
+		{name: "Below", v: -1, want: color.RGBA{B: 127, A: 0xff}},
+		{name: "Above", v: 2, want: color.RGBA{R: 127, A: 0xff}},
+		{name: "NaN", v: float32(math.NaN()), want: color.RGBA{B: 127, A: 0xff}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Jet(tc.v); got != tc.want {
+				t.Errorf("expected %v, got: %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float32
+		width  int
+		height int
+		want   []uint8
+	}{
+		{name: "Normalized", values: []float32{-1, 0, 1, 3}, width: 2, height: 2, want: []uint8{0, 63, 127, 255}},
+		{name: "Row", values: []float32{2, 4, 3}, width: 3, height: 1, want: []uint8{0, 255, 127}},
+		{name: "Constant", values: []float32{5, 5}, width: 1, height: 2, want: []uint8{0, 0}},
+		{name: "NaN", values: []float32{0, float32(math.NaN()), 1}, width: 3, height: 1, want: []uint8{0, 0, 255}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := Heatmap(tc.values, tc.width, tc.height, Gray)
+			if err != nil {
+				t.Fatalf("failed to render heatmap: %v", err)
+			}
+
+			if b := img.Bounds(); b != image.Rect(0, 0, tc.width, tc.height) {
+				t.Fatalf("expected bounds %v, got: %v", image.Rect(0, 0, tc.width, tc.height), b)
+			}
+
+			got := make([]uint8, len(tc.values))
+			for i := range got {
+				got[i] = img.RGBAAt(i%tc.width, i/tc.width).R
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %v, got: %v", tc.want, got)
+			}
+		})
+	}
+
+	img, err := Heatmap([]float32{0, 1}, 2, 1, nil)
+	if err != nil {
+		t.Fatalf("failed to render heatmap: %v", err)
+	}
+	if c := img.RGBAAt(1, 0); c != Jet(1) {
+		t.Errorf("expected default Jet color %v, got: %v", Jet(1), c)
+	}
+
+	for _, size := range [][3]int{{3, 2, 2}, {4, 0, 4}, {0, -1, 0}} {
+		if _, err := Heatmap(make([]float32, size[0]), size[1], size[2], nil); err == nil {
+			t.Errorf("expected error for %d values of %dx%d map, got nil", size[0], size[1], size[2])
+		}
+	}
+}
+
+func TestSegmentation(t *testing.T) {
+	palette := []color.RGBA{red, blue}
+
+	img, err := Segmentation([]int{0, 1, 2, -1}, 2, 2, palette)
+	if err != nil {
+		t.Fatalf("failed to render segmentation: %v", err)
+	}
+
+	want := []color.RGBA{red, blue, red, {}}
+	for i, c := range want {
+		if got := img.RGBAAt(i%2, i/2); got != c {
+			t.Errorf("pixel %d: expected %v, got: %v", i, c, got)
+		}
+	}
+
+	img, err = Segmentation([]int{0, len(defaultPalette) + 1}, 2, 1, nil)
+	if err != nil {
+		t.Fatalf("failed to render segmentation: %v", err)
+	}
+	if got := img.RGBAAt(1, 0); got != defaultPalette[1] {
+		t.Errorf("expected default palette color %v, got: %v", defaultPalette[1], got)
+	}
+
+	if _, err := Segmentation([]int{0, 1, 2}, 2, 2, nil); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestArgmax(t *testing.T) {
+	// 2x1 map of 3 classes, the second pixel ties between classes 0 and 2
+	got, err := Argmax([]float32{0.1, 0.7, 0.2, 0.4, 0.2, 0.4}, 2, 1, 3)
+	if err != nil {
+		t.Fatalf("failed to compute argmax: %v", err)
+	}
+
+	if want := []int{1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got: %v", want, got)
+	}
+
+	if _, err := Argmax(make([]float32, 5), 2, 1, 3); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+
+	if _, err := Argmax(nil, 2, 1, 0); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	// the overlay is smaller than the image, so it is scaled up
+	overlay := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for i := 0; i < len(overlay.Pix); i += 4 {
+		overlay.Pix[i+2], overlay.Pix[i+3] = 0xff, 0xff
+	}
+
+	tests := []struct {
+		name    string
+		opacity float64
+		want    color.RGBA
+	}{
+		{name: "Transparent", opacity: 0, want: white},
+		{name: "Opaque", opacity: 1, want: blue},
+		{name: "Clamped", opacity: 2, want: blue},
+		{name: "Half", opacity: 0.5, want: color.RGBA{R: 0x80, G: 0x80, B: 0xff, A: 0xff}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			img := canvas(8, 4)
+			Overlay(img, overlay, tc.opacity)
+
+			for y := 0; y < 4; y++ {
+				for x := 0; x < 8; x++ {
+					if got := img.RGBAAt(x, y); got != tc.want {
+						t.Fatalf("pixel (%d,%d): expected %v, got: %v", x, y, tc.want, got)
+					}
+				}
+			}
+		})
+	}
+}
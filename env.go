@@ -0,0 +1,110 @@
+package ncs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EnvDeviceIndex is the environment variable which sets the index of the device opened by NewDeviceFromEnv and NewSession
+	EnvDeviceIndex = "NCS_DEVICE_INDEX"
+	// EnvDeviceSerial is the environment variable which sets the USB serial number of the device opened
+	// by NewDeviceFromEnv and NewSession. It takes precedence over NCS_DEVICE_INDEX. See WithSerial for more details.
+	EnvDeviceSerial = "NCS_DEVICE_SERIAL"
+	// EnvFifoDepth is the environment variable which sets the number of elements of the FIFOs allocated
+	// by AllocateWithFifosDefault and NewSession. It must be a positive integer.
+	EnvFifoDepth = "NCS_FIFO_DEPTH"
+	// EnvLogLevel is the environment variable which sets the NCSDK log level in NewDeviceFromEnv and NewSession.
+	// It can be set to debug, info, warn, error or fatal.
+	EnvLogLevel = "NCS_LOG_LEVEL"
+)
+
+const (
+	// defaultDeviceIndex is the device index used if EnvDeviceIndex is not set
+	defaultDeviceIndex = 0
+	// defaultFifoDepth is the FIFO depth used if EnvFifoDepth is not set
+	defaultFifoDepth = 2
+)
+
+// NewDeviceFromEnv creates new NCS device handle configured via environment variables.
+// The device index is read from NCS_DEVICE_INDEX and defaults to 0. If NCS_DEVICE_SERIAL is set,
+// the device with the given USB serial number is selected instead, unless opts contain WithIndex or WithSerial.
+// If NCS_LOG_LEVEL is set, the NCSDK log level is set to its value before the device is created.
+// The handle can be further configured via opts. This allows containerized deployments to be tuned without code changes.
+// It returns error if any of the environment variables has invalid value or if it fails to create the device handle.
+func NewDeviceFromEnv(opts ...Opt) (*Device, error) {
+	index, err := envInt(EnvDeviceIndex, defaultDeviceIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if serial := os.Getenv(EnvDeviceSerial); serial != "" {
+		var o handleOpts
+		for _, apply := range opts {
+			apply(&o)
+		}
+
+		if o.set&(optIndex|optSerial) == 0 {
+			opts = append([]Opt{WithSerial(serial)}, opts...)
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvLogLevel); ok {
+		level, err := parseLogLevel(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := SetLogLevel(level); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewDevice(index, opts...)
+}
+
+// envFifoDepth returns the FIFO depth read from NCS_FIFO_DEPTH or defaultFifoDepth if it is not set
+func envFifoDepth() (int, error) {
+	return envPositiveInt(EnvFifoDepth, defaultFifoDepth)
+}
+
+// envInt returns non-negative integer value of environment variable key or def if it is not set
+func envInt(key string, def int) (int, error) {
+	return envIntMin(key, def, 0, "non-negative")
+}
+
+// envPositiveInt returns positive integer value of environment variable key or def if it is not set
+func envPositiveInt(key string, def int) (int, error) {
+	return envIntMin(key, def, 1, "positive")
+}
+
+// envIntMin returns integer value of environment variable key or def if it is not set.
+// It returns error if the value is not an integer or if it is smaller than min; desc describes min in the error.
+func envIntMin(key string, def, min int, desc string) (int, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min {
+		return 0, fmt.Errorf("Invalid %s value %q: expected %s integer: %w", key, v, desc, ErrInvalidParameters)
+	}
+
+	return n, nil
+}
+
+// parseLogLevel parses log level name, e.g. debug or LOG_DEBUG, into LogLevel
+func parseLogLevel(v string) (LogLevel, error) {
+	name := strings.TrimPrefix(strings.ToUpper(v), "LOG_")
+
+	for l := LogDebug; l <= LogFatal; l++ {
+		if "LOG_"+name == l.String() {
+			return l, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Invalid %s value %q: expected debug, info, warn, error or fatal: %w", EnvLogLevel, v, ErrInvalidParameters)
+}
@@ -0,0 +1,68 @@
+package ncs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEnvFifoDepth(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+		err   error
+	}{
+		{name: "Unset", value: "", want: defaultFifoDepth},
+		{name: "Valid", value: "4", want: 4},
+		{name: "Zero", value: "0", err: ErrInvalidParameters},
+		{name: "Negative", value: "-1", err: ErrInvalidParameters},
+		{name: "NonNumeric", value: "two", err: ErrInvalidParameters},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(EnvFifoDepth, tc.value)
+
+			got, err := envFifoDepth()
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error: %v, got: %v", tc.err, err)
+			}
+
+			if got != tc.want {
+				t.Errorf("expected %d, got: %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEnvInt(t *testing.T) {
+	const key = "NCS_TEST_INT"
+
+	tests := []struct {
+		name  string
+		value string
+		want  int
+		err   error
+	}{
+		{name: "Unset", value: "", want: 7},
+		{name: "Zero", value: "0", want: 0},
+		{name: "Valid", value: "3", want: 3},
+		{name: "Negative", value: "-1", err: ErrInvalidParameters},
+		{name: "NonNumeric", value: "1x", err: ErrInvalidParameters},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(key, tc.value)
+
+			got, err := envInt(key, 7)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error: %v, got: %v", tc.err, err)
+			}
+
+			if got != tc.want {
+				t.Errorf("expected %d, got: %d", tc.want, got)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/milosgajdos/ncs"
+	"github.com/milosgajdos/ncs/results"
 	"gocv.io/x/gocv"
 )
 
@@ -162,14 +163,7 @@ func main() {
 		err = e
 		return
 	}
-	// find max and value
-	max := result[0]
-	idx := 0
-	for i, val := range result {
-		if max < val {
-			max = val
-			idx = i
-		}
-	}
-	log.Printf("Prediction: %v, Probability: %v", labels[idx], max)
+	// find the top prediction
+	top := results.New(result[:], labels).Top(1)[0]
+	log.Printf("Prediction: %v, Probability: %v", top.Label, top.Score)
 }
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/milosgajdos/ncs"
+	"github.com/milosgajdos/ncs/labels"
+	"github.com/milosgajdos/ncs/results"
+	"gocv.io/x/gocv"
+)
+
+var (
+	graphPath = flag.String("graph", "../caffe-squeezenet/squeezenet_graph", "path to the compiled SqueezeNet graph")
+	imgDir    = flag.String("images", "../caffe-squeezenet", "directory with the images to classify")
+)
+
+var meanBGR = []float64{0.40787054 * 255.0, 0.45752458 * 255.0, 0.48109378 * 255.0}
+var imgSize = image.Point{227, 227}
+
+// stick is a device along with the graph allocated on it
+type stick struct {
+	index int
+	dev   *ncs.Device
+	graph *ncs.Graph
+	queue *ncs.FifoQueue
+}
+
+// openStick opens the device with the given index and allocates the graph on it
+func openStick(index int, graphData []byte) (*stick, error) {
+	dev, err := ncs.NewDevice(index)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dev.Open(); err != nil {
+		dev.Destroy()
+		return nil, err
+	}
+
+	graph, err := ncs.NewGraph(fmt.Sprintf("SqueezenetGraph%d", index))
+	if err != nil {
+		dev.Close()
+		dev.Destroy()
+		return nil, err
+	}
+
+	queue, err := graph.AllocateWithFifosDefault(dev, graphData)
+	if err != nil {
+		graph.Destroy()
+		dev.Close()
+		dev.Destroy()
+		return nil, err
+	}
+
+	return &stick{index: index, dev: dev, graph: graph, queue: queue}, nil
+}
+
+// Close releases all the stick resources
+func (s *stick) Close() {
+	s.queue.Close()
+	s.graph.Destroy()
+	s.dev.Close()
+	s.dev.Destroy()
+}
+
+// readImages returns the paths of all the PNG and JPEG images in dir
+func readImages(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, f := range files {
+		switch strings.ToLower(filepath.Ext(f.Name())) {
+		case ".png", ".jpg", ".jpeg":
+			paths = append(paths, filepath.Join(dir, f.Name()))
+		}
+	}
+
+	return paths, nil
+}
+
+// meanCenter pre-preprocesses image so each of its layer pixels have zero mean
+func meanCenter(img gocv.Mat, meanBGR []float64) gocv.Mat {
+	r, c := img.Rows(), img.Cols()
+	meanB, meanG, meanR := meanBGR[0], meanBGR[1], meanBGR[2]
+
+	// create mean centered image layer by layer
+	meanBMat := gocv.NewMatWithSizeFromScalar(gocv.NewScalar(meanB, meanB, meanB, 0.0), r, c, gocv.MatTypeCV64F)
+	defer meanBMat.Close()
+	meanGMat := gocv.NewMatWithSizeFromScalar(gocv.NewScalar(meanG, meanG, meanG, 0.0), r, c, gocv.MatTypeCV64F)
+	defer meanGMat.Close()
+	meanRMat := gocv.NewMatWithSizeFromScalar(gocv.NewScalar(meanR, meanR, meanR, 0.0), r, c, gocv.MatTypeCV64F)
+	defer meanRMat.Close()
+
+	meanMatImg := gocv.NewMat()
+	gocv.Merge([]gocv.Mat{meanBMat, meanGMat, meanRMat}, &meanMatImg)
+	defer meanMatImg.Close()
+
+	floatImg := gocv.NewMat()
+	defer floatImg.Close()
+	img.ConvertTo(&floatImg, gocv.MatTypeCV64F)
+
+	zeroMean := gocv.NewMat()
+	gocv.Subtract(floatImg, meanMatImg, &zeroMean)
+
+	return zeroMean
+}
+
+// prepareImg reads the image stored in path and preprocesses it for NCS
+func prepareImg(path string) ([]byte, error) {
+	img := gocv.IMRead(path, gocv.IMReadColor)
+	defer img.Close()
+	if img.Empty() {
+		return nil, fmt.Errorf("failed to read image %s", path)
+	}
+
+	// resize the image
+	resized := gocv.NewMat()
+	defer resized.Close()
+	gocv.Resize(img, &resized, imgSize, 0, 0, gocv.InterpolationDefault)
+	// zero-mean centering
+	zeroMeanImg := meanCenter(resized, meanBGR)
+	defer zeroMeanImg.Close()
+	// convert to FP32 for NCS
+	fp32Image := gocv.NewMat()
+	defer fp32Image.Close()
+	zeroMeanImg.ConvertTo(&fp32Image, gocv.MatTypeCV32F)
+
+	return fp32Image.ToBytes(), nil
+}
+
+// score is the top prediction for the image stored in path
+type score struct {
+	path  string
+	stick int
+	top   results.Prediction
+}
+
+// classify classifies all the images received from paths on stick s and sends the results to scores
+func classify(s *stick, paths <-chan string, scores chan<- score, labels []string) error {
+	for path := range paths {
+		data, err := prepareImg(path)
+		if err != nil {
+			return err
+		}
+
+		res, err := ncs.Infer(s.graph, s.queue, data, path)
+		if err != nil {
+			return err
+		}
+
+		var out [1000]float32
+		err = binary.Read(bytes.NewReader(res.Data), binary.LittleEndian, &out)
+		res.Release()
+		if err != nil {
+			return err
+		}
+
+		scores <- score{path: res.MetaData, stick: s.index, top: results.New(out[:], labels).Top(1)[0]}
+	}
+
+	return nil
+}
+
+func main() {
+	flag.Parse()
+
+	var err error
+	defer func() {
+		if err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+	}()
+
+	log.Printf("Attempting to enumerate NCS devices")
+	devices, e := ncs.Devices()
+	if e != nil {
+		err = e
+		return
+	}
+	if len(devices) == 0 {
+		err = fmt.Errorf("no NCS devices found")
+		return
+	}
+	log.Printf("Found %d NCS devices", len(devices))
+
+	graphData, e := ioutil.ReadFile(*graphPath)
+	if e != nil {
+		err = e
+		return
+	}
+
+	var sticks []*stick
+	defer func() {
+		for _, s := range sticks {
+			s.Close()
+		}
+	}()
+
+	for _, d := range devices {
+		log.Printf("Attempting to allocate NCS graph on device %d: %s", d.Index, d.Name)
+		s, e := openStick(d.Index, graphData)
+		if e != nil {
+			err = e
+			return
+		}
+		sticks = append(sticks, s)
+	}
+	log.Printf("NCS graph successfully allocated on %d devices", len(sticks))
+
+	imgPaths, e := readImages(*imgDir)
+	if e != nil {
+		err = e
+		return
+	}
+	log.Printf("Attempting to classify %d images from %s", len(imgPaths), *imgDir)
+
+	paths := make(chan string)
+	scores := make(chan score, len(imgPaths))
+	errs := make(chan error, len(sticks))
+
+	start := time.Now()
+
+	imagenet := labels.ImageNet()
+
+	var wg sync.WaitGroup
+	for _, s := range sticks {
+		wg.Add(1)
+		go func(s *stick) {
+			defer wg.Done()
+			if err := classify(s, paths, scores, imagenet); err != nil {
+				errs <- fmt.Errorf("device %d: %v", s.index, err)
+			}
+		}(s)
+	}
+
+	go func() {
+		defer close(paths)
+		for _, p := range imgPaths {
+			select {
+			case paths <- p:
+			case e := <-errs:
+				errs <- e
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(scores)
+	elapsed := time.Since(start)
+
+	select {
+	case err = <-errs:
+		return
+	default:
+	}
+
+	perStick := make(map[int]int)
+	var all []score
+	for s := range scores {
+		perStick[s.stick]++
+		all = append(all, s)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].path < all[j].path })
+	for _, s := range all {
+		log.Printf("%s: %s (%.4f) [device %d]", s.path, s.top.Label, s.top.Score, s.stick)
+	}
+
+	for _, s := range sticks {
+		log.Printf("Device %d classified %d images", s.index, perStick[s.index])
+	}
+	log.Printf("Classified %d images in %s: %.2f images/s", len(all), elapsed, float64(len(all))/elapsed.Seconds())
+
+}
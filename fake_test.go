@@ -0,0 +1,523 @@
+//go:build !ncsdevice
+
+package ncs
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGraph is the graph data allocated on the fake devices, which accept any non-empty graph
+var fakeGraph = []byte("fake graph")
+
+// fakeInput is the input of the fake identity network: 1x1x2x2 FP32 tensor
+var fakeInput = func() []byte {
+	data := make([]byte, 4*int32Size)
+	for i, val := range []float32{1, 2, 3, 4} {
+		binary.LittleEndian.PutUint32(data[i*int32Size:], math.Float32bits(val))
+	}
+
+	return data
+}()
+
+// openFakeDevice creates and opens the first device and destroys it once the test finishes.
+// It skips the test unless the package is linked against the fake NCSDK library in testdata/mvnc.
+func openFakeDevice(t *testing.T) *Device {
+	t.Helper()
+
+	d, err := NewDevice(0)
+	if err != nil {
+		t.Skipf("fake NCSDK library not linked, run make test: %v", err)
+	}
+
+	if name, err := GetOption[string](d, RODeviceName); err != nil || !strings.HasPrefix(name, "fake-") {
+		d.Destroy()
+		t.Skipf("fake NCSDK library not linked, run make test")
+	}
+
+	if err := d.Open(); err != nil {
+		d.Destroy()
+		t.Fatalf("failed to open device: %v", err)
+	}
+
+	t.Cleanup(func() {
+		d.Close()
+		d.Destroy()
+	})
+
+	return d
+}
+
+// allocateFakeGraph allocates fake graph with its FIFO queue on d and destroys them once the test finishes
+func allocateFakeGraph(t *testing.T, d *Device) (*Graph, *FifoQueue) {
+	t.Helper()
+
+	g, err := NewGraph("FakeGraph")
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	q, err := g.AllocateWithFifosDefault(d, fakeGraph)
+	if err != nil {
+		g.Destroy()
+		t.Fatalf("failed to allocate graph: %v", err)
+	}
+
+	t.Cleanup(func() {
+		q.Close()
+		g.Destroy()
+	})
+
+	return g, q
+}
+
+func TestFakeDevice(t *testing.T) {
+	d := openFakeDevice(t)
+
+	if state, err := d.State(); err != nil || state != DeviceOpened {
+		t.Errorf("expected state %s, got: %s, %v", DeviceOpened, state, err)
+	}
+
+	stats, err := d.ThermalStats()
+	if err != nil {
+		t.Fatalf("failed to query thermal stats: %v", err)
+	}
+
+	if len(stats) != ThermalBufferSize {
+		t.Errorf("expected %d thermal stats, got: %d", ThermalBufferSize, len(stats))
+	}
+
+	if hw, err := d.HWVersion(); err != nil || hw != MA2450 {
+		t.Errorf("expected HW version %s, got: %s, %v", MA2450, hw, err)
+	}
+
+	if _, err := d.FirmwareVersion(); err != nil {
+		t.Errorf("failed to query firmware version: %v", err)
+	}
+
+	if _, err := d.MaxExecutors(); !errors.Is(err, ErrUnsupportedOption) || !errors.Is(err, ErrUnsupportedFeature) {
+		t.Errorf("expected error %v, got: %v", ErrUnsupportedOption, err)
+	}
+
+	if _, err := d.GetOption(RODeviceMaxExecutors); !errors.Is(err, ErrUnsupportedOption) {
+		t.Errorf("expected error %v, got: %v", ErrUnsupportedOption, err)
+	}
+
+	if info, err := d.DebugInfo(); err != nil || info != "" {
+		t.Errorf("expected empty debug info, got: %q, %v", info, err)
+	}
+
+	if data, err := d.GetOption(RODeviceDebugInfo); err != nil || len(data) > DebugBufferSize {
+		t.Errorf("expected at most %d bytes of debug info, got: %d, %v", DebugBufferSize, len(data), err)
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("failed to close device: %v", err)
+	}
+
+	if err := d.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected error %v, got: %v", ErrClosed, err)
+	}
+}
+
+func TestFakeDeviceInfo(t *testing.T) {
+	d := openFakeDevice(t)
+
+	// the fake firmware does not support RODeviceMaxExecutors, which must not fail the whole query
+	info, err := d.Info()
+	if err != nil {
+		t.Fatalf("failed to query device info: %v", err)
+	}
+
+	if info.Name != "fake-0" || info.State != DeviceOpened || info.HWVersion != MA2450 {
+		t.Errorf("unexpected device info: %+v", info)
+	}
+
+	if info.MemorySize == 0 || info.MaxGraphs == 0 || info.MaxFifos == 0 {
+		t.Errorf("expected device capacities, got: %+v", info)
+	}
+
+	if info.MaxExecutors != 0 {
+		t.Errorf("expected unsupported max executors to be unset, got: %d", info.MaxExecutors)
+	}
+}
+
+func TestFakeDeviceNotFound(t *testing.T) {
+	openFakeDevice(t)
+
+	if _, err := NewDevice(1); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("expected error %v, got: %v", ErrDeviceNotFound, err)
+	}
+}
+
+func TestFakeDeviceWithIndex(t *testing.T) {
+	openFakeDevice(t)
+
+	t.Setenv("MVNC_FAKE_DEVICE_COUNT", "2")
+
+	d, err := NewDevice(0, WithIndex(1))
+	if err != nil {
+		t.Fatalf("failed to create device: %v", err)
+	}
+	defer d.Destroy()
+
+	if name, err := GetOption[string](d, RODeviceName); err != nil || name != "fake-1" {
+		t.Errorf("expected device %q, got: %q, %v", "fake-1", name, err)
+	}
+
+	if _, err := NewDevice(0, WithAllocator(nil)); !errors.Is(err, ErrInvalidParameters) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidParameters, err)
+	}
+}
+
+func TestFakeDevices(t *testing.T) {
+	openFakeDevice(t)
+
+	t.Setenv("MVNC_FAKE_DEVICE_COUNT", "2")
+
+	devs, err := Devices()
+	if err != nil {
+		t.Fatalf("failed to query devices: %v", err)
+	}
+
+	if len(devs) != 2 {
+		t.Fatalf("expected 2 devices, got: %d", len(devs))
+	}
+
+	for i, dd := range devs {
+		if dd.Index != i {
+			t.Errorf("expected device index %d, got: %d", i, dd.Index)
+		}
+
+		if name := fmt.Sprintf("fake-%d", i); dd.Name != name {
+			t.Errorf("expected device name %q, got: %q", name, dd.Name)
+		}
+	}
+}
+
+func TestFakeGlobalOptions(t *testing.T) {
+	openFakeDevice(t)
+
+	level, err := GetLogLevel()
+	if err != nil {
+		t.Fatalf("failed to query log level: %v", err)
+	}
+	defer SetLogLevel(level)
+
+	if err := SetLogLevel(LogError); err != nil {
+		t.Fatalf("failed to set log level: %v", err)
+	}
+
+	if level, err := GetLogLevel(); err != nil || level != LogError {
+		t.Errorf("expected log level %s, got: %s, %v", LogError, level, err)
+	}
+}
+
+func TestFakeGraph(t *testing.T) {
+	d := openFakeDevice(t)
+	g, q := allocateFakeGraph(t, d)
+
+	if state, err := g.State(); err != nil || state != GraphAllocated {
+		t.Errorf("expected state %s, got: %s, %v", GraphAllocated, state, err)
+	}
+
+	descs, err := g.InputTensorDescs()
+	if err != nil {
+		t.Fatalf("failed to query input tensor descriptors: %v", err)
+	}
+
+	if len(descs) != 1 || descs[0].Width != 2 || descs[0].Height != 2 {
+		t.Errorf("unexpected input tensor descriptors: %+v", descs)
+	}
+
+	out, err := g.Infer(q, fakeInput)
+	if err != nil {
+		t.Fatalf("failed to run inference: %v", err)
+	}
+
+	if !bytes.Equal(out.Data, fakeInput) {
+		t.Errorf("expected output %v, got: %v", fakeInput, out.Data)
+	}
+
+	res, err := Infer(g, q, fakeInput, "image.jpg")
+	if err != nil {
+		t.Fatalf("failed to run inference: %v", err)
+	}
+	defer res.Release()
+
+	if res.MetaData != "image.jpg" {
+		t.Errorf("expected metadata %q, got: %q", "image.jpg", res.MetaData)
+	}
+
+	if times, err := g.InferenceTimes(); err != nil || len(times) == 0 {
+		t.Errorf("expected inference times, got: %v, %v", times, err)
+	}
+}
+
+func TestFakeFifo(t *testing.T) {
+	d := openFakeDevice(t)
+
+	f, err := NewFifo("FakeFifo", FifoHostWO)
+	if err != nil {
+		t.Fatalf("failed to create FIFO: %v", err)
+	}
+	defer f.Destroy()
+
+	if err := f.Allocate(d, NewTensorDesc(1, 1, 2, 2, FifoFP32), 2); err != nil {
+		t.Fatalf("failed to allocate FIFO: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := f.WriteElem(fakeInput, i); err != nil {
+			t.Fatalf("failed to write element %d: %v", i, err)
+		}
+	}
+
+	elem, err := f.ReadElem()
+	if err != nil {
+		t.Fatalf("failed to read element: %v", err)
+	}
+
+	if !bytes.Equal(elem.Data, fakeInput) || elem.MetaData != 0 {
+		t.Errorf("unexpected element: %v, %v", elem.Data, elem.MetaData)
+	}
+
+	buf := make([]byte, len(fakeInput))
+	n, meta, err := f.ReadElemInto(buf)
+	if err != nil {
+		t.Fatalf("failed to read element: %v", err)
+	}
+
+	if !bytes.Equal(buf[:n], fakeInput) || meta != 1 {
+		t.Errorf("unexpected element: %v, %v", buf[:n], meta)
+	}
+
+	if _, _, err := f.ReadElemInto(make([]byte, 1)); err == nil {
+		t.Errorf("expected error reading into short buffer")
+	}
+}
+
+// metaTokenCount returns the number of outstanding FIFO element metadata tokens
+func metaTokenCount() int {
+	metaTokens.Lock()
+	defer metaTokens.Unlock()
+
+	return len(metaTokens.values)
+}
+
+func TestFakeFifoDestroyReleasesMetaData(t *testing.T) {
+	d := openFakeDevice(t)
+
+	f, err := NewFifo("FakeFifo", FifoHostWO)
+	if err != nil {
+		t.Fatalf("failed to create FIFO: %v", err)
+	}
+
+	const elems = 3
+	if err := f.Allocate(d, NewTensorDesc(1, 1, 2, 2, FifoFP32), elems); err != nil {
+		f.Destroy()
+		t.Fatalf("failed to allocate FIFO: %v", err)
+	}
+
+	before := metaTokenCount()
+
+	for i := 0; i < elems; i++ {
+		if err := f.WriteElem(fakeInput, i); err != nil {
+			t.Fatalf("failed to write element %d: %v", i, err)
+		}
+	}
+
+	if n := metaTokenCount(); n != before+elems {
+		t.Errorf("expected %d metadata tokens, got: %d", before+elems, n)
+	}
+
+	if err := f.Destroy(); err != nil {
+		t.Fatalf("failed to destroy FIFO: %v", err)
+	}
+
+	if n := metaTokenCount(); n != before {
+		t.Errorf("expected %d metadata tokens after destroying FIFO, got: %d", before, n)
+	}
+}
+
+func TestFakeDeviceCloseReleasesMetaData(t *testing.T) {
+	d := openFakeDevice(t)
+	g, q := allocateFakeGraph(t, d)
+
+	before := metaTokenCount()
+
+	for i := 0; i < 2; i++ {
+		if err := g.QueueInferenceWithFifoElem(q, fakeInput, i); err != nil {
+			t.Fatalf("failed to queue inference: %v", err)
+		}
+	}
+
+	if n := metaTokenCount(); n != before+2 {
+		t.Errorf("expected %d metadata tokens, got: %d", before+2, n)
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("failed to close device: %v", err)
+	}
+
+	if n := metaTokenCount(); n != before {
+		t.Errorf("expected %d metadata tokens after closing device, got: %d", before, n)
+	}
+}
+
+func TestFakeReadElemAbandoned(t *testing.T) {
+	d := openFakeDevice(t)
+	_, q := allocateFakeGraph(t, d)
+
+	delay := 200 * time.Millisecond
+	t.Setenv("MVNC_FAKE_READ_DELAY_MS", "200")
+
+	q.Out.SetTimeout(10 * time.Millisecond)
+
+	start := time.Now()
+	if _, err := q.Out.ReadElem(); !errors.Is(err, ErrCallAbandoned) {
+		t.Fatalf("expected error %v, got: %v", ErrCallAbandoned, err)
+	}
+
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("expected abandoned read to return before %s, took: %s", delay, elapsed)
+	}
+
+	if err := q.Out.Destroy(); err != nil {
+		t.Fatalf("failed to destroy FIFO: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Errorf("expected destroy to wait for the abandoned read for %s, took: %s", delay, elapsed)
+	}
+}
+
+func TestFakeSession(t *testing.T) {
+	// the fake devices can be opened repeatedly, so the session can open the device opened by the test
+	openFakeDevice(t)
+
+	s, err := NewSession(fakeGraph)
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	out, err := s.Infer(fakeInput)
+	if err != nil {
+		t.Fatalf("failed to run inference: %v", err)
+	}
+
+	if !bytes.Equal(out.Data, fakeInput) {
+		t.Errorf("expected output %v, got: %v", fakeInput, out.Data)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("failed to close session: %v", err)
+	}
+
+	if _, err := s.Infer(fakeInput); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected error %v, got: %v", ErrClosed, err)
+	}
+}
+
+func TestFakeSessionContext(t *testing.T) {
+	openFakeDevice(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewSessionContext(ctx, fakeGraph); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got: %v", context.Canceled, err)
+	}
+
+	// the handles created before the context error are torn down, so the session can be created again
+	s, err := NewSessionContext(context.Background(), fakeGraph)
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("failed to close session: %v", err)
+	}
+}
+
+func TestFakeDeviceFromEnv(t *testing.T) {
+	openFakeDevice(t)
+
+	t.Setenv("MVNC_FAKE_DEVICE_COUNT", "2")
+	t.Setenv(EnvDeviceIndex, "1")
+
+	d, err := NewDeviceFromEnv()
+	if err != nil {
+		t.Fatalf("failed to create device: %v", err)
+	}
+	defer d.Destroy()
+
+	if name, err := GetOption[string](d, RODeviceName); err != nil || name != "fake-1" {
+		t.Errorf("expected device %q, got: %q, %v", "fake-1", name, err)
+	}
+
+	t.Setenv(EnvDeviceSerial, "no-such-serial")
+
+	if _, err := NewDeviceFromEnv(); err == nil {
+		t.Errorf("expected error selecting device with unknown serial")
+	}
+
+	// explicit index takes precedence over the environment
+	dd, err := NewDeviceFromEnv(WithIndex(0))
+	if err != nil {
+		t.Fatalf("failed to create device: %v", err)
+	}
+	defer dd.Destroy()
+
+	if name, err := GetOption[string](dd, RODeviceName); err != nil || name != "fake-0" {
+		t.Errorf("expected device %q, got: %q, %v", "fake-0", name, err)
+	}
+}
+
+func TestFakeSessionEnv(t *testing.T) {
+	openFakeDevice(t)
+
+	t.Setenv("MVNC_FAKE_DEVICE_COUNT", "2")
+	t.Setenv(EnvDeviceIndex, "1")
+	t.Setenv(EnvFifoDepth, "3")
+
+	outOpts := &FifoOpts{FifoHostRO, FifoFP32, 4}
+	s, err := NewSession(fakeGraph, WithFifoOpts(nil, outOpts))
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	defer s.Close()
+
+	if name, err := GetOption[string](s.Device, RODeviceName); err != nil || name != "fake-1" {
+		t.Errorf("expected device %q, got: %q, %v", "fake-1", name, err)
+	}
+
+	for _, tc := range []struct {
+		f    *Fifo
+		want uint
+	}{{s.Queue.In, 3}, {s.Queue.Out, 4}} {
+		if capacity, err := GetOption[uint](tc.f, ROFifoCapacity); err != nil || capacity != tc.want {
+			t.Errorf("expected FIFO capacity %d, got: %d, %v", tc.want, capacity, err)
+		}
+	}
+
+	t.Setenv(EnvDeviceSerial, "no-such-serial")
+
+	// explicit device index takes precedence over the environment
+	ss, err := NewSession(fakeGraph, WithDeviceIndex(0))
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	defer ss.Close()
+
+	if name, err := GetOption[string](ss.Device, RODeviceName); err != nil || name != "fake-0" {
+		t.Errorf("expected device %q, got: %q, %v", "fake-0", name, err)
+	}
+}
@@ -7,8 +7,12 @@ package ncs
 import "C"
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -20,6 +24,34 @@ type FifoQueue struct {
 	Out *Fifo
 }
 
+// FifoWriter writes elements to a FIFO. It mirrors the host access of FifoHostWO FIFOs,
+// such as the inbound FIFO of a FifoQueue. It is implemented by Fifo.
+type FifoWriter interface {
+	// WriteElem writes an element along with its metadata to the FIFO
+	WriteElem(data []byte, metaData interface{}) error
+	// WriteElemContext writes an element along with its metadata to the FIFO until ctx is done
+	WriteElemContext(ctx context.Context, data []byte, metaData interface{}) error
+}
+
+// FifoReader reads elements from a FIFO. It mirrors the host access of FifoHostRO FIFOs,
+// such as the outbound FIFO of a FifoQueue. It is implemented by Fifo.
+type FifoReader interface {
+	// ReadElem reads an element along with its metadata from the FIFO
+	ReadElem() (*Tensor, error)
+	// ReadElemContext reads an element along with its metadata from the FIFO until ctx is done
+	ReadElemContext(ctx context.Context) (*Tensor, error)
+}
+
+// Writer returns the inbound FIFO of the queue as FifoWriter
+func (f *FifoQueue) Writer() FifoWriter {
+	return f.In
+}
+
+// Reader returns the outbound FIFO of the queue as FifoReader
+func (f *FifoQueue) Reader() FifoReader {
+	return f.Out
+}
+
 // FifoType defines FIFO access types.
 //
 // For more information:
@@ -58,6 +90,11 @@ func (fd FifoDataType) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler interface.
+func (fd FifoDataType) MarshalText() ([]byte, error) {
+	return []byte(fd.String()), nil
+}
+
 // FifoState represents FIFO state
 type FifoState int
 
@@ -80,6 +117,11 @@ func (fs FifoState) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler interface.
+func (fs FifoState) MarshalText() ([]byte, error) {
+	return []byte(fs.String()), nil
+}
+
 // FifoOption is FIFO option which can be used to query and set various FIFO properties.
 // The options starting with RW are both gettable and settable.
 // The options starting with RO are only gettable.
@@ -133,7 +175,7 @@ var fifoOptSize = map[Option]uint{
 func (fo FifoOption) String() string {
 	switch fo {
 	case RWFifoType:
-		return "RW FIFO type"
+		return "RW_FIFO_TYPE"
 	case RWFifoConsumerCount:
 		return "RW_FIFO_CONSUMER_COUNT"
 	case RWFifoDataType:
@@ -141,7 +183,7 @@ func (fo FifoOption) String() string {
 	case RWFifoNoBlock:
 		return "RW_FIFO_NO_BLOCK"
 	case ROFifoCapacity:
-		return "RW_FIFO_CAPACITY"
+		return "RO_FIFO_CAPACITY"
 	case ROFifoReadFillLevel:
 		return "RO_FIFO_READ_FILL_LEVEL"
 	case ROFifoWriteFillLevel:
@@ -161,6 +203,11 @@ func (fo FifoOption) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler interface.
+func (fo FifoOption) MarshalText() ([]byte, error) {
+	return []byte(fo.String()), nil
+}
+
 // Value returns option value as integer
 func (fo FifoOption) Value() int {
 	return int(fo)
@@ -168,9 +215,9 @@ func (fo FifoOption) Value() int {
 
 // Decode decodes options data encoded in raw bytes and returns it in its native type.
 // The returned data can be asserted into its native type.
-// If the data contains more than one element you need to specify the number of expected elements via count.
-// It returns error if the data fails to be decoded into the option native type.
-func (fo FifoOption) Decode(data []byte, count int) (interface{}, error) {
+// The number of elements of array options is derived from the length of the data.
+// It returns error if the data is too short or if it fails to be decoded into the option native type.
+func (fo FifoOption) Decode(data []byte) (interface{}, error) {
 	buf := bytes.NewReader(data)
 
 	switch fo {
@@ -184,6 +231,10 @@ func (fo FifoOption) Decode(data []byte, count int) (interface{}, error) {
 		ROFifoElemDataSize,
 		ROFifoState:
 
+		if err := checkDataLen(fo, data, C.sizeof_int, 1); err != nil {
+			return nil, err
+		}
+
 		var val uint32
 		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
 			return nil, err
@@ -193,39 +244,20 @@ func (fo FifoOption) Decode(data []byte, count int) (interface{}, error) {
 		return uint(val), nil
 
 	case ROFifoName:
-		return string(data), nil
+		return decodeString(data), nil
 
-	case RWFifoHostTensorDesc:
-		var val struct {
-			BatchSize uint32
-			Channels  uint32
-			Width     uint32
-			Height    uint32
-			Size      uint32
-			CStride   uint32
-			WStride   uint32
-			HStride   uint32
-			DataType  int32
-		}
+	case ROFifoGraphTensorDesc,
+		RWFifoHostTensorDesc:
 
-		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
+		tds, err := decodeTensorDescs(fo, data)
+		if err != nil {
 			return nil, err
 		}
 
-		return &TensorDesc{
-			BatchSize: uint(val.BatchSize),
-			Channels:  uint(val.Channels),
-			Width:     uint(val.Width),
-			Height:    uint(val.Height),
-			Size:      uint(val.Size),
-			CStride:   uint(val.CStride),
-			WStride:   uint(val.WStride),
-			HStride:   uint(val.HStride),
-			DataType:  FifoDataType(val.DataType),
-		}, nil
+		return &tds[0], nil
 
 	default:
-		return nil, fmt.Errorf("Unable to decode FIFO option data: %s", fo)
+		return nil, fmt.Errorf("Unable to decode FIFO option data: %s: %w", fo, ErrInvalidParameters)
 	}
 }
 
@@ -241,17 +273,53 @@ type FifoOpts struct {
 
 // Fifo is NCSDK FIFO queue
 type Fifo struct {
-	name   string
-	handle unsafe.Pointer
-	device *Device
+	// mu guards the handle and FIFO state
+	mu      sync.RWMutex
+	name    string
+	typ     FifoType
+	handle  unsafe.Pointer
+	device  *Device
+	devGen  uint64
+	state   FifoState
+	retry   *RetryPolicy
+	timeout time.Duration
+	logger  Logger
+	alloc   Allocator
+	// elemSize caches the element data size read by ReadElem, it is 0 if it has not been queried yet
+	elemSize uint64
+	// calls pins the FIFO while its calls run in the background
+	calls pin
+}
+
+// valid returns true if the queue and both of its FIFOs are not nil
+func (f *FifoQueue) valid() bool {
+	return f != nil && f.In != nil && f.Out != nil
+}
+
+// rlock read locks both FIFOs of the queue
+func (f *FifoQueue) rlock() {
+	f.In.mu.RLock()
+	f.Out.mu.RLock()
+}
+
+// runlock read unlocks both FIFOs of the queue
+func (f *FifoQueue) runlock() {
+	f.Out.mu.RUnlock()
+	f.In.mu.RUnlock()
 }
 
 // NewFifo creates new FIFO queue with given name and returns it
-// It returns error if it fails to create new queue
+// The FIFO can be configured via opts.
+// It returns error if any of opts is not supported by FIFOs or if it fails to create new queue
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncFifoCreate.html
-func NewFifo(name string, t FifoType) (*Fifo, error) {
+func NewFifo(name string, t FifoType, opts ...Opt) (*Fifo, error) {
+	o, err := newHandleOpts("fifo", opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	var handle unsafe.Pointer
 
 	_name := C.CString(name)
@@ -260,10 +328,16 @@ func NewFifo(name string, t FifoType) (*Fifo, error) {
 	s := C.ncs_FifoCreate(_name, C.ncFifoType(t), &handle)
 
 	if Status(s) != StatusOK {
-		return nil, fmt.Errorf("Failed to create new FIFO: %s", Status(s))
+		err := newError("Create", "fifo", s)
+		err.Name = name
+		return nil, err
 	}
 
-	return &Fifo{name: name, handle: handle}, nil
+	f := &Fifo{name: name, typ: t, handle: handle, retry: o.retry, timeout: o.timeout, logger: o.logger, alloc: o.alloc}
+	trackHandle(f, "fifo", name)
+	logf(f.logger, "fifo %s: created", name)
+
+	return f, nil
 }
 
 // Allocate allocates memory for a FIFO for the specified device based on the number of elements the FIFO will hold and tensorDesc, which describes the expected shape of the FIFO’s elements
@@ -272,6 +346,37 @@ func NewFifo(name string, t FifoType) (*Fifo, error) {
 // More information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncFifoAllocate.html
 func (f *Fifo) Allocate(d *Device, td *TensorDesc, numElem uint) error {
+	return f.AllocateContext(context.Background(), d, td, numElem)
+}
+
+// AllocateContext allocates memory for a FIFO for the specified device.
+// It returns ctx error if ctx is done before the FIFO is allocated. See Allocate for more details.
+func (f *Fifo) AllocateContext(ctx context.Context, d *Device, td *TensorDesc, numElem uint) error {
+	if f == nil || d == nil {
+		return invalidHandle("Allocate", "fifo")
+	}
+
+	if td == nil {
+		return invalidParams("Allocate", "fifo")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if f.handle == nil || d.handle == nil {
+		return ErrClosed
+	}
+
+	if f.state != FifoCreated {
+		return &StateError{Op: "Allocate", Resource: "fifo", State: f.state}
+	}
+	if d.state != DeviceOpened {
+		return &StateError{Op: "Allocate", Resource: "device", State: d.state}
+	}
+
 	_td := C.struct_ncTensorDescriptor_t{
 		n:         C.uint(td.BatchSize),
 		c:         C.uint(td.Channels),
@@ -284,35 +389,133 @@ func (f *Fifo) Allocate(d *Device, td *TensorDesc, numElem uint) error {
 		dataType:  C.ncFifoDataType(td.DataType),
 	}
 
-	s := C.ncs_FifoAllocate(f.handle, d.handle, &_td, C.uint(numElem))
+	handle, devHandle := f.handle, d.handle
+	s, err := f.retry.do(ctx, func() (C.int, error) {
+		return monitor(ctx, 0, func() C.int {
+			td := _td
+			return C.ncs_FifoAllocate(handle, devHandle, &td, C.uint(numElem))
+		}, &f.calls, &d.calls)
+	})
+
+	if err != nil {
+		return err
+	}
 
 	if Status(s) != StatusOK {
-		return fmt.Errorf("Failed to allocate FIFO: %s", Status(s))
+		return f.newError("Allocate", s)
 	}
 
+	f.device = d
+	f.devGen = d.generation()
+	f.state = FifoAllocated
+	atomic.StoreUint64(&f.elemSize, 0)
+	logf(f.logger, "fifo %s: allocated", f.name)
+
 	return nil
 }
 
+// stale returns true if the FIFO device has been closed or destroyed since the FIFO was allocated.
+// It must be called with f.mu held.
+func (f *Fifo) stale() bool {
+	return f.device != nil && f.device.generation() != f.devGen
+}
+
+// newError returns new Error for the failed FIFO operation op.
+// It fetches the FIFO device debug information if the operation failed with StatusMyriadError.
+// It must be called with f.mu held.
+func (f *Fifo) newError(op string, s C.int) *Error {
+	err := newError(op, "fifo", s)
+	err.Name = f.name
+	if f.device != nil {
+		err.Index = f.device.index
+
+		if err.Status == StatusMyriadError && !f.stale() {
+			err.DebugInfo = f.device.debugInfo()
+		}
+	}
+
+	return err
+}
+
+// SetTimeout sets the timeout of the potentially blocking FIFO calls.
+// Zero duration means the package default timeout is used. See SetDefaultTimeout for more details.
+func (f *Fifo) SetTimeout(t time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.timeout = t
+}
+
+// SetRetryPolicy sets the policy used to retry the FIFO calls which fail with StatusBusy or StatusTimeout.
+// Passing nil disables retries.
+func (f *Fifo) SetRetryPolicy(p *RetryPolicy) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.retry = p
+}
+
+// SetAllocator sets the allocator of the buffers which hold the data of the elements read from the FIFO.
+// Passing nil means the package default is used. See SetDefaultAllocator for more details.
+func (f *Fifo) SetAllocator(a Allocator) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.alloc = a
+}
+
+// Handle returns the underlying NCSDK struct ncFifoHandle_t pointer or nil if the FIFO has been destroyed.
+//
+// This is an escape hatch for advanced use: it allows calling NCSDK functions which are not wrapped
+// by this package. The returned pointer must not be used after the FIFO has been destroyed and
+// it must not be passed to ncFifoDestroy; use the Fifo methods instead.
+// Changes made to the FIFO through the returned pointer are not tracked by the Fifo.
+func (f *Fifo) Handle() unsafe.Pointer {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.handle
+}
+
 // GetOptions queries FIFO options and returns it encoded in a byte slice
 // It returns error if it fails to retrieve the options
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncFifoGetOption.html
 func (f *Fifo) GetOption(opt FifoOption) ([]byte, error) {
-	if opt == RWFifoNoBlock {
-		return nil, fmt.Errorf("Option %s not implemented", opt)
+	if f == nil {
+		return nil, invalidHandle("GetOption", "fifo")
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.handle == nil {
+		return nil, ErrClosed
 	}
 
-	var data unsafe.Pointer
-	var dataLen C.uint
+	if f.stale() {
+		return nil, ErrStaleHandle
+	}
 
-	s := C.ncs_FifoGetOption(f.handle, C.int(opt), data, &dataLen)
+	if opt == RWFifoNoBlock {
+		return nil, fmt.Errorf("Option %s not implemented: %w", opt, ErrUnsupportedFeature)
+	}
+
+	data, s, err := queryOption("fifo", f.handle, opt, fifoOptSize[opt])
+	if err != nil {
+		return nil, err
+	}
 
-	if Status(s) == StatusInvalidDataLength {
-		return f.GetOptionWithByteSize(opt, fifoOptSize[opt]*uint(dataLen))
+	if Status(s) != StatusOK {
+		return nil, f.newError("GetOption", s)
 	}
 
-	return nil, fmt.Errorf("Failed to read %s option: %s", opt, Status(s))
+	return data, nil
 }
 
 // GetOptionsWithSize queries NCS fifo options and returns it encoded in a byte slice of size elements.
@@ -322,61 +525,473 @@ func (f *Fifo) GetOption(opt FifoOption) ([]byte, error) {
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncFifoGetOption.html
 func (f *Fifo) GetOptionWithByteSize(opt FifoOption, size uint) ([]byte, error) {
+	if f == nil {
+		return nil, invalidHandle("GetOption", "fifo")
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.handle == nil {
+		return nil, ErrClosed
+	}
+
+	if f.stale() {
+		return nil, ErrStaleHandle
+	}
+
 	if opt == RWFifoNoBlock {
-		return nil, fmt.Errorf("Option %s not implemented", opt)
+		return nil, fmt.Errorf("Option %s not implemented: %w", opt, ErrUnsupportedFeature)
 	}
 
 	return getOption("fifo", f.handle, opt, size)
 }
 
+// SetOption sets the value of FIFO option opt to data, which must contain the option value
+// encoded in the option native type, i.e. the same way the value is returned by GetOption.
+// The value is validated before it is sent to NCSDK. All options except RWFifoHostTensorDesc
+// must be set before the FIFO is allocated.
+// It returns error if the option is read only, if the value is out of range or if it fails to set the option.
+//
+// For more information:
+// https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncFifoSetOption.html
+func (f *Fifo) SetOption(opt FifoOption, data []byte) error {
+	if f == nil {
+		return invalidHandle("SetOption", "fifo")
+	}
+
+	if err := validateFifoOption(opt, data); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.handle == nil {
+		return ErrClosed
+	}
+
+	if f.state != FifoCreated && opt != RWFifoHostTensorDesc {
+		return &StateError{Op: "SetOption", Resource: "fifo", State: f.state}
+	}
+
+	s := C.ncs_FifoSetOption(f.handle, C.int(opt), unsafe.Pointer(&data[0]), C.uint(len(data)))
+
+	if Status(s) != StatusOK {
+		return f.newError("SetOption", s)
+	}
+
+	// host tensor descriptor changes the size of the elements read from the FIFO
+	if opt == RWFifoHostTensorDesc {
+		atomic.StoreUint64(&f.elemSize, 0)
+	}
+
+	return nil
+}
+
+// validateFifoOption checks if data contains valid value of FIFO option opt
+func validateFifoOption(opt FifoOption, data []byte) error {
+	switch opt {
+	case RWFifoType,
+		RWFifoConsumerCount,
+		RWFifoDataType,
+		RWFifoHostTensorDesc:
+	case RWFifoNoBlock:
+		return fmt.Errorf("Option %s not implemented: %w", opt, ErrUnsupportedFeature)
+	default:
+		return fmt.Errorf("Option %s is read only: %w", opt, ErrInvalidParameters)
+	}
+
+	if len(data) != int(fifoOptSize[opt]) {
+		return fmt.Errorf("Invalid %s value: expected %d bytes, got %d: %w", opt, fifoOptSize[opt], len(data), ErrInvalidParameters)
+	}
+
+	val, err := opt.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	switch opt {
+	case RWFifoType:
+		if t := FifoType(val.(uint)); t != FifoHostRO && t != FifoHostWO {
+			return fmt.Errorf("Invalid %s value: unknown FIFO type %d: %w", opt, t, ErrInvalidParameters)
+		}
+	case RWFifoConsumerCount:
+		if val.(uint) < 1 {
+			return fmt.Errorf("Invalid %s value: FIFO must have at least one consumer: %w", opt, ErrInvalidParameters)
+		}
+	case RWFifoDataType:
+		if dt := FifoDataType(val.(uint)); dt != FifoFP16 && dt != FifoFP32 {
+			return fmt.Errorf("Invalid %s value: unknown data type %d: %w", opt, dt, ErrInvalidParameters)
+		}
+	case RWFifoHostTensorDesc:
+		if err := val.(*TensorDesc).Validate(); err != nil {
+			return fmt.Errorf("Invalid %s value: %w", opt, err)
+		}
+	}
+
+	return nil
+}
+
 // WriteElem writes an element to a FIFO, usually an input tensor for inference along with some metadata
+// The metadata is kept on the host and returned in the Tensor read from the outbound FIFO.
 // If it fails to write the element it returns error
+// If the call times out, data must not be modified as the call may still be reading it in the background.
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncFifoWriteElem.html
 func (f *Fifo) WriteElem(data []byte, metaData interface{}) error {
-	dataLen := C.uint(len(data))
+	return f.WriteElemContext(context.Background(), data, metaData)
+}
+
+// WriteElemContext writes an element to a FIFO along with some metadata.
+// It returns ctx error if ctx is done before the element is written. See WriteElem for more details.
+// If ctx is done, data must not be modified as the call may still be reading it in the background.
+func (f *Fifo) WriteElemContext(ctx context.Context, data []byte, metaData interface{}) error {
+	if f == nil {
+		return invalidHandle("WriteElem", "fifo")
+	}
+
+	if len(data) == 0 {
+		return invalidParams("WriteElem", "fifo")
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 
-	s := C.ncs_FifoWriteElem(f.handle, unsafe.Pointer(&data[0]), &dataLen, unsafe.Pointer(&metaData))
+	if f.handle == nil {
+		return ErrClosed
+	}
+
+	if f.state != FifoAllocated {
+		return &StateError{Op: "WriteElem", Resource: "fifo", State: f.state}
+	}
+
+	if f.stale() {
+		return ErrStaleHandle
+	}
+
+	handle := f.handle
+	s, err := f.retry.do(ctx, func() (C.int, error) {
+		if err := ctx.Err(); err != nil {
+			return C.int(StatusOK), err
+		}
+
+		// every attempt carries its own token, so the elements written by an abandoned attempt
+		// and by a subsequent retry never share the same token
+		token := newMetaToken(metaData, f.device, f)
+
+		s, err := monitor(ctx, callTimeout(f.timeout), func() C.int {
+			dataLen := C.uint(len(data))
+			return C.ncs_FifoWriteElem(handle, unsafe.Pointer(&data[0]), &dataLen, token)
+		}, &f.calls, devicePin(f.device))
+
+		// abandoned call might still succeed in the background, so its token can't be released
+		if err == nil && Status(s) != StatusOK {
+			releaseMetaToken(token)
+		}
+
+		return s, err
+	})
+
+	if err != nil {
+		return err
+	}
 
 	if Status(s) != StatusOK {
-		return fmt.Errorf("Failed to write FIFO element: %s", Status(s))
+		return f.newError("WriteElem", s)
 	}
 
 	return nil
 }
 
 // ReadElem reads an element from a FIFO, usually the result of an inference as a tensor, along with the associated user-defined data
+// The user-defined data is returned in Tensor MetaData.
+// If the FIFO has an allocator set, the tensor data is allocated by it and the tensor must be released via Release.
 // If it fails to read the element it returns error
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncFifoReadElem.html
 func (f *Fifo) ReadElem() (*Tensor, error) {
-	opts, err := f.GetOptionWithByteSize(ROFifoElemDataSize, C.sizeof_int)
-	if err != nil {
-		return nil, err
+	return f.ReadElemContext(context.Background())
+}
+
+// ReadElemContext reads an element from a FIFO along with the associated user-defined data.
+// It returns ctx error if ctx is done before the element is read. See ReadElem for more details.
+func (f *Fifo) ReadElemContext(ctx context.Context) (*Tensor, error) {
+	if f == nil {
+		return nil, invalidHandle("ReadElem", "fifo")
 	}
 
-	elemSize, err := ROFifoElemDataSize.Decode(opts, 1)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.handle == nil {
+		return nil, ErrClosed
+	}
+
+	if f.state != FifoAllocated {
+		return nil, &StateError{Op: "ReadElem", Resource: "fifo", State: f.state}
+	}
+
+	if f.stale() {
+		return nil, ErrStaleHandle
+	}
+
+	elemSize, err := f.readElemSize()
 	if err != nil {
 		return nil, err
 	}
 
-	var metaData unsafe.Pointer
-	size := C.uint(elemSize.(uint))
-	data := C.malloc(C.sizeof_char * C.ulong(elemSize.(uint)))
+	var data []byte
+	var metaData interface{}
 
-	s := C.ncs_FifoReadElem(f.handle, data, &size, &metaData)
+	handle, alloc := f.handle, elemAllocator(f.alloc)
+	s, err := f.retry.do(ctx, func() (C.int, error) {
+		// buf and meta are only read once the monitored call has returned
+		var buf []byte
+		var meta interface{}
+
+		s, err := monitor(ctx, callTimeout(f.timeout), func() C.int {
+			var token C.uintptr_t
+			size := C.uint(elemSize)
+			out := C.malloc(C.sizeof_char * C.ulong(elemSize))
+			defer C.free(out)
+
+			s := C.ncs_FifoReadElem(handle, out, &size, &token)
+			if Status(s) == StatusOK {
+				buf = readElemBuf(alloc, out, int(size))
+				meta = releaseMetaToken(token)
+			}
+
+			return s
+		}, &f.calls, devicePin(f.device))
+
+		if err == nil && Status(s) == StatusOK {
+			data, metaData = buf, meta
+		}
+
+		return s, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
 
 	if Status(s) != StatusOK {
-		return nil, fmt.Errorf("Failed to read FIFO element: %s", Status(s))
+		return nil, f.newError("ReadElem", s)
 	}
 
 	return &Tensor{
-		Data: C.GoBytes(data, C.int(size)),
+		Data:     data,
+		MetaData: metaData,
+		alloc:    alloc,
 	}, nil
 }
 
+// ReadElemInto reads an element from a FIFO directly into buf, which avoids allocating a new buffer for every read.
+// It returns the number of bytes read into buf along with the associated user-defined data.
+// The FIFO allocator is not used by ReadElemInto.
+// It returns error if buf is too small to hold the element or if it fails to read the element.
+//
+// If the call times out, it returns ErrCallAbandoned and the read is not retried. The abandoned read keeps
+// running in the background: the element it eventually reads is lost and it may still be writing into buf,
+// hence buf must be discarded rather than reused. Once the FIFO has been destroyed buf is safe to reuse,
+// as Destroy waits for the abandoned calls to return.
+//
+// For more information:
+// https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncFifoReadElem.html
+func (f *Fifo) ReadElemInto(buf []byte) (int, interface{}, error) {
+	return f.ReadElemIntoContext(context.Background(), buf)
+}
+
+// ReadElemIntoContext reads an element from a FIFO directly into buf.
+// It returns ctx error if ctx is done before the element is read. See ReadElemInto for more details.
+// If ctx is done the read is abandoned the same way as when it times out, so buf must be discarded.
+func (f *Fifo) ReadElemIntoContext(ctx context.Context, buf []byte) (int, interface{}, error) {
+	if f == nil {
+		return 0, nil, invalidHandle("ReadElem", "fifo")
+	}
+
+	if len(buf) == 0 {
+		return 0, nil, invalidParams("ReadElem", "fifo")
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.handle == nil {
+		return 0, nil, ErrClosed
+	}
+
+	if f.state != FifoAllocated {
+		return 0, nil, &StateError{Op: "ReadElem", Resource: "fifo", State: f.state}
+	}
+
+	if f.stale() {
+		return 0, nil, ErrStaleHandle
+	}
+
+	elemSize, err := f.readElemSize()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if uint(len(buf)) < elemSize {
+		return 0, nil, fmt.Errorf("Buffer too small: expected at least %d bytes, got %d: %w", elemSize, len(buf), ErrInvalidParameters)
+	}
+
+	var n int
+	var metaData interface{}
+
+	// only the reads which returned a transient status without reading the element are retried,
+	// abandoned reads are not, so buf is never written by more than one call at a time
+	handle := f.handle
+	s, err := f.retry.do(ctx, func() (C.int, error) {
+		// size and meta are only read once the monitored call has returned
+		var size C.uint
+		var meta interface{}
+
+		s, err := monitor(ctx, callTimeout(f.timeout), func() C.int {
+			var token C.uintptr_t
+			dataLen := C.uint(elemSize)
+
+			s := C.ncs_FifoReadElem(handle, unsafe.Pointer(&buf[0]), &dataLen, &token)
+			if Status(s) == StatusOK {
+				size, meta = dataLen, releaseMetaToken(token)
+			}
+
+			return s
+		}, &f.calls, devicePin(f.device))
+
+		if err == nil && Status(s) == StatusOK {
+			n, metaData = int(size), meta
+		}
+
+		return s, err
+	})
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if Status(s) != StatusOK {
+		return 0, nil, f.newError("ReadElem", s)
+	}
+
+	return n, metaData, nil
+}
+
+// readElemSize returns the size of the elements read from the FIFO. The size is queried
+// on the first read only and cached, so the subsequent reads avoid the round-trip to the device.
+// It must be called with f.mu held.
+func (f *Fifo) readElemSize() (uint, error) {
+	if size := atomic.LoadUint64(&f.elemSize); size != 0 {
+		return uint(size), nil
+	}
+
+	opts, err := getOption("fifo", f.handle, ROFifoElemDataSize, C.sizeof_int)
+	if err != nil {
+		return 0, err
+	}
+
+	val, err := ROFifoElemDataSize.Decode(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	size := val.(uint)
+	atomic.StoreUint64(&f.elemSize, uint64(size))
+
+	return size, nil
+}
+
+// readElemBuf copies size bytes of the element read into C buffer out into a buffer allocated by alloc.
+// If alloc is nil the buffer is allocated on the Go heap.
+func readElemBuf(alloc Allocator, out unsafe.Pointer, size int) []byte {
+	if alloc == nil {
+		return C.GoBytes(out, C.int(size))
+	}
+
+	buf := alloc.Alloc(size)
+	copy(buf, unsafe.Slice((*byte)(out), size))
+
+	return buf
+}
+
+// Recover discards the elements which have been left in the outbound FIFO of the queue, for example
+// after a failed inference or a read which has been abandoned, so that the subsequent reads return
+// the results of the subsequently queued inferences. The metadata of the discarded elements is released.
+// The inbound FIFO can not be drained by the host: its elements are consumed by the queued inferences.
+// It returns error if it fails to read the outbound FIFO.
+func (f *FifoQueue) Recover() error {
+	return f.RecoverContext(context.Background())
+}
+
+// RecoverContext discards the elements which have been left in the outbound FIFO of the queue.
+// It returns ctx error if ctx is done before the FIFO is drained. See Recover for more details.
+func (f *FifoQueue) RecoverContext(ctx context.Context) error {
+	if !f.valid() {
+		return invalidHandle("Recover", "fifo")
+	}
+
+	return f.Out.drain(ctx)
+}
+
+// drain reads and discards all the elements which are available in the FIFO read buffer.
+// The metadata of the discarded elements is released.
+func (f *Fifo) drain(ctx context.Context) error {
+	f.mu.RLock()
+	state := f.state
+	f.mu.RUnlock()
+
+	if state != FifoAllocated {
+		return &StateError{Op: "Drain", Resource: "fifo", State: state}
+	}
+
+	opts, err := f.GetOptionWithByteSize(ROFifoReadFillLevel, C.sizeof_int)
+	if err != nil {
+		return err
+	}
+
+	level, err := ROFifoReadFillLevel.Decode(opts)
+	if err != nil {
+		return err
+	}
+
+	for i := uint(0); i < level.(uint); i++ {
+		t, err := f.ReadElemContext(ctx)
+		if err != nil {
+			return err
+		}
+		t.Release()
+	}
+
+	return nil
+}
+
+// checkInputSize checks if the size of data matches the element size of FIFO f.
+// It must be called with f.mu held.
+func checkInputSize(f *Fifo, data []byte) error {
+	opts, err := getOption("fifo", f.handle, ROFifoElemDataSize, C.sizeof_int)
+	if err != nil {
+		return err
+	}
+
+	elemSize, err := ROFifoElemDataSize.Decode(opts)
+	if err != nil {
+		return err
+	}
+
+	if uint(len(data)) != elemSize.(uint) {
+		return fmt.Errorf("Invalid input size: FIFO element size is %d bytes, got %d bytes; "+
+			"make sure the input dimensions match the graph input tensor and the data type matches the FIFO data type: %w",
+			elemSize.(uint), len(data), ErrInvalidParameters)
+	}
+
+	return nil
+}
+
 // RemoveElem removes an element from a FIFO
 // If it fails to remove the element it returns error
 // THIS FUNCTION IS NOT IMPLEMENTED YET
@@ -384,20 +999,177 @@ func (f *Fifo) ReadElem() (*Tensor, error) {
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncFifoRemoveElem.html
 func (f *Fifo) RemoveElem() error {
-	return fmt.Errorf("%s", StatusUnsupportedFeature)
+	return &Error{Op: "RemoveElem", Resource: "fifo", Index: -1, Status: StatusUnsupportedFeature}
+}
+
+// FifoInfo aggregates the information about NCS FIFO
+type FifoInfo struct {
+	// Name is the name of the FIFO
+	Name string `json:"name"`
+	// State is the FIFO state as reported by NCSDK
+	State FifoState `json:"state"`
+	// Type is the FIFO access type
+	Type FifoType `json:"type"`
+	// DataType is the FIFO data type
+	DataType FifoDataType `json:"data_type"`
+	// ConsumerCount is the number of consumers of elements before the element is removed
+	ConsumerCount uint `json:"consumer_count"`
+	// Capacity is the maximum number of elements in the FIFO
+	Capacity uint `json:"capacity"`
+	// ReadFillLevel is the number of elements in the FIFO read buffer
+	ReadFillLevel uint `json:"read_fill_level"`
+	// WriteFillLevel is the number of elements in the FIFO write buffer
+	WriteFillLevel uint `json:"write_fill_level"`
+	// ElemDataSize is the size of the FIFO elements in bytes
+	ElemDataSize uint `json:"elem_data_size"`
+	// GraphTensorDesc describes the shape of the FIFO elements as seen by the graph
+	GraphTensorDesc TensorDesc `json:"graph_tensor_desc"`
+}
+
+// Info queries all the FIFO information options at once and returns them aggregated in FifoInfo.
+// The FIFO must be allocated before its information can be queried.
+// It returns error if it fails to query any of the options.
+func (f *Fifo) Info() (*FifoInfo, error) {
+	if f == nil {
+		return nil, invalidHandle("GetOption", "fifo")
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.handle == nil {
+		return nil, ErrClosed
+	}
+
+	if f.state != FifoAllocated {
+		return nil, &StateError{Op: "GetOption", Resource: "fifo", State: f.state}
+	}
+
+	if f.stale() {
+		return nil, ErrStaleHandle
+	}
+
+	query := func(opt FifoOption) (interface{}, error) {
+		data, s, err := queryOption("fifo", f.handle, opt, fifoOptSize[opt])
+		if err != nil {
+			return nil, err
+		}
+
+		if Status(s) != StatusOK {
+			return nil, f.newError("GetOption", s)
+		}
+
+		return opt.Decode(data)
+	}
+
+	info := &FifoInfo{}
+
+	uints := []struct {
+		opt FifoOption
+		val *uint
+	}{
+		{RWFifoConsumerCount, &info.ConsumerCount},
+		{ROFifoCapacity, &info.Capacity},
+		{ROFifoReadFillLevel, &info.ReadFillLevel},
+		{ROFifoWriteFillLevel, &info.WriteFillLevel},
+		{ROFifoElemDataSize, &info.ElemDataSize},
+	}
+
+	for _, u := range uints {
+		val, err := query(u.opt)
+		if err != nil {
+			return nil, err
+		}
+		*u.val = val.(uint)
+	}
+
+	val, err := query(ROFifoName)
+	if err != nil {
+		return nil, err
+	}
+	info.Name = val.(string)
+
+	if val, err = query(ROFifoState); err != nil {
+		return nil, err
+	}
+	info.State = FifoState(val.(uint))
+
+	if val, err = query(RWFifoType); err != nil {
+		return nil, err
+	}
+	info.Type = FifoType(val.(uint))
+
+	if val, err = query(RWFifoDataType); err != nil {
+		return nil, err
+	}
+	info.DataType = FifoDataType(val.(uint))
+
+	if val, err = query(ROFifoGraphTensorDesc); err != nil {
+		return nil, err
+	}
+	info.GraphTensorDesc = *val.(*TensorDesc)
+
+	return info, nil
 }
 
 // Destroy destroys NCS FIFO handle and frees associated resources.
 // This function must be called for every FIFO handle that was initialized with NewFifo()
+// It returns ErrClosed if the FIFO has already been destroyed.
+// It blocks until the abandoned calls which use the handle have returned.
+// The metadata of the elements written to the FIFO which have not been read is released,
+// so it is not returned if any of those elements is still read from another FIFO.
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncFifoDestroy.html
 func (f *Fifo) Destroy() error {
+	if f == nil {
+		return invalidHandle("Destroy", "fifo")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.handle == nil {
+		return ErrClosed
+	}
+
+	// the background calls must return before the handle is freed
+	f.calls.wait(context.Background())
+
 	s := C.ncs_FifoDestroy(&f.handle)
 
 	if Status(s) != StatusOK {
-		return fmt.Errorf("Failed to destroy FIFO: %s", Status(s))
+		return f.newError("Destroy", s)
 	}
 
+	f.handle = nil
+	releaseFifoMetaTokens(f)
+	untrackHandle(f)
+	logf(f.logger, "fifo %s: destroyed", f.name)
+
 	return nil
 }
+
+// Close destroys the FIFO handle. It implements io.Closer interface, so the FIFO can be
+// released along with other resources by generic resource management code. See Destroy for more details.
+func (f *Fifo) Close() error {
+	return f.Destroy()
+}
+
+// Close destroys both FIFOs of the queue. It implements io.Closer interface.
+// Both FIFOs are destroyed even if destroying the inbound FIFO fails. It returns the first error encountered.
+// FIFO queues must be closed before the graph they were allocated with is destroyed.
+func (f *FifoQueue) Close() error {
+	if !f.valid() {
+		return invalidHandle("Destroy", "fifo")
+	}
+
+	inErr := f.In.Destroy()
+	outErr := f.Out.Destroy()
+
+	if inErr != nil {
+		return inErr
+	}
+
+	return outErr
+}
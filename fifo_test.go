@@ -0,0 +1,52 @@
+package ncs
+
+import "testing"
+
+func TestFifoOptionDecode(t *testing.T) {
+	scalar := []FifoOption{
+		RWFifoType,
+		RWFifoConsumerCount,
+		RWFifoDataType,
+		RWFifoNoBlock,
+		ROFifoCapacity,
+		ROFifoReadFillLevel,
+		ROFifoWriteFillLevel,
+		ROFifoElemDataSize,
+		ROFifoState,
+	}
+
+	for _, opt := range scalar {
+		t.Run(opt.String(), func(t *testing.T) {
+			testDecode(t, opt, scalarDecodeTests(t))
+		})
+	}
+
+	t.Run(ROFifoName.String(), func(t *testing.T) {
+		testDecode(t, ROFifoName, stringDecodeTests())
+	})
+
+	for _, opt := range []FifoOption{ROFifoGraphTensorDesc, RWFifoHostTensorDesc} {
+		t.Run(opt.String(), func(t *testing.T) {
+			testDecode(t, opt, tensorDescDecodeTests(t, false))
+		})
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		testDecode(t, FifoOption(-1), []decodeTest{
+			{name: "Exact", data: encode(t, uint32(1)), err: ErrInvalidParameters},
+		})
+	})
+}
+
+func FuzzFifoOptionDecode(f *testing.F) {
+	for opt := RWFifoType; opt <= RWFifoHostTensorDesc; opt++ {
+		f.Add(int(opt), []byte(nil))
+		f.Add(int(opt), make([]byte, int32Size))
+		f.Add(int(opt), make([]byte, tensorDescRawSize))
+	}
+
+	f.Fuzz(func(t *testing.T, opt int, data []byte) {
+		// any error is fine as long as decoding malformed data never panics
+		_, _ = FifoOption(opt).Decode(data)
+	})
+}
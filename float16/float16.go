@@ -0,0 +1,219 @@
+// Package float16 provides IEEE 754 half precision floating point number type,
+// which is the data type of the FP16 FIFO elements, along with conversions and arithmetic.
+package float16
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Float16 is IEEE 754 half precision floating point number
+type Float16 uint16
+
+const (
+	// Inf is positive infinity
+	Inf Float16 = 0x7c00
+	// NegInf is negative infinity
+	NegInf Float16 = 0xfc00
+	// NaN is quiet not-a-number value
+	NaN Float16 = 0x7e00
+	// Max is the largest finite half precision number
+	Max Float16 = 0x7bff
+	// SmallestNonzero is the smallest positive subnormal half precision number
+	SmallestNonzero Float16 = 0x0001
+)
+
+// FromBits returns Float16 with the given IEEE 754 binary representation
+func FromBits(b uint16) Float16 {
+	return Float16(b)
+}
+
+// Bits returns IEEE 754 binary representation of f
+func (f Float16) Bits() uint16 {
+	return uint16(f)
+}
+
+// FromFloat32 converts f to the nearest half precision number, rounding ties to even.
+// The values which are too large to be represented are converted to infinity.
+func FromFloat32(f float32) Float16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int32(b>>23) & 0xff
+	frac := b & 0x7fffff
+
+	if exp == 0xff {
+		if frac != 0 {
+			return Float16(sign) | NaN
+		}
+		return Float16(sign) | Inf
+	}
+
+	// rebias the exponent
+	e := exp - 127 + 15
+
+	if e >= 0x1f {
+		return Float16(sign) | Inf
+	}
+
+	if e <= 0 {
+		// the value is too small to be represented even as subnormal number
+		if e < -10 {
+			return Float16(sign)
+		}
+
+		// subnormal number: add the implicit leading bit and shift the mantissa into place
+		m := frac | 0x800000
+		shift := uint32(14 - e)
+		half := m >> shift
+		rem := m & (1<<shift - 1)
+		halfway := uint32(1) << (shift - 1)
+		if rem > halfway || (rem == halfway && half&1 == 1) {
+			half++
+		}
+
+		return Float16(sign | uint16(half))
+	}
+
+	half := uint32(e)<<10 | frac>>13
+	rem := frac & 0x1fff
+	// rounding may carry into the exponent, which correctly produces the next power of two or infinity
+	if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		half++
+	}
+
+	return Float16(sign | uint16(half))
+}
+
+// FromFloat64 converts f to the nearest half precision number
+func FromFloat64(f float64) Float16 {
+	return FromFloat32(float32(f))
+}
+
+// Float32 converts f to float32. The conversion is exact.
+func (f Float16) Float32() float32 {
+	sign := uint32(f>>15) << 31
+	exp := uint32(f>>10) & 0x1f
+	frac := uint32(f) & 0x3ff
+
+	switch {
+	case exp == 0x1f:
+		// infinity or NaN
+		return math.Float32frombits(sign | 0xff<<23 | frac<<13)
+	case exp == 0 && frac == 0:
+		// signed zero
+		return math.Float32frombits(sign)
+	case exp == 0:
+		// subnormal number: normalize it
+		exp = 1
+		for frac&0x400 == 0 {
+			frac <<= 1
+			exp--
+		}
+		frac &= 0x3ff
+		fallthrough
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | frac<<13)
+	}
+}
+
+// Float64 converts f to float64. The conversion is exact.
+func (f Float16) Float64() float64 {
+	return float64(f.Float32())
+}
+
+// IsNaN returns true if f is not-a-number value
+func (f Float16) IsNaN() bool {
+	return f&0x7c00 == 0x7c00 && f&0x3ff != 0
+}
+
+// IsInf returns true if f is infinity with the sign given by sign: positive if sign > 0,
+// negative if sign < 0 and either if sign == 0
+func (f Float16) IsInf(sign int) bool {
+	return (sign >= 0 && f == Inf) || (sign <= 0 && f == NegInf)
+}
+
+// Neg returns f with its sign flipped
+func (f Float16) Neg() Float16 {
+	return f ^ 0x8000
+}
+
+// Abs returns the absolute value of f
+func (f Float16) Abs() Float16 {
+	return f &^ 0x8000
+}
+
+// Add returns f + g rounded to half precision
+func (f Float16) Add(g Float16) Float16 {
+	return FromFloat32(f.Float32() + g.Float32())
+}
+
+// Sub returns f - g rounded to half precision
+func (f Float16) Sub(g Float16) Float16 {
+	return FromFloat32(f.Float32() - g.Float32())
+}
+
+// Mul returns f * g rounded to half precision
+func (f Float16) Mul(g Float16) Float16 {
+	return FromFloat32(f.Float32() * g.Float32())
+}
+
+// Div returns f / g rounded to half precision
+func (f Float16) Div(g Float16) Float16 {
+	return FromFloat32(f.Float32() / g.Float32())
+}
+
+// Less returns true if f is less than g. NaN values are not less than any value.
+func (f Float16) Less(g Float16) bool {
+	return f.Float32() < g.Float32()
+}
+
+// String implements fmt.Stringer interface
+func (f Float16) String() string {
+	return fmt.Sprint(f.Float32())
+}
+
+// Decode decodes little-endian FP16 data, e.g. the data of FP16 FIFO elements, into a slice of Float16.
+// It returns error if the length of data is not a multiple of 2 bytes.
+func Decode(data []byte) ([]Float16, error) {
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("Invalid FP16 data length: %d bytes is not a multiple of 2", len(data))
+	}
+
+	res := make([]Float16, len(data)/2)
+	for i := range res {
+		res[i] = Float16(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+
+	return res, nil
+}
+
+// Encode encodes s into little-endian FP16 data which can be written to FP16 FIFOs
+func Encode(s []Float16) []byte {
+	data := make([]byte, 2*len(s))
+	for i, f := range s {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(f))
+	}
+
+	return data
+}
+
+// FromFloat32s converts s to a slice of Float16
+func FromFloat32s(s []float32) []Float16 {
+	res := make([]Float16, len(s))
+	for i, f := range s {
+		res[i] = FromFloat32(f)
+	}
+
+	return res
+}
+
+// ToFloat32s converts s to a slice of float32
+func ToFloat32s(s []Float16) []float32 {
+	res := make([]float32, len(s))
+	for i, f := range s {
+		res[i] = f.Float32()
+	}
+
+	return res
+}
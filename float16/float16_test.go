@@ -0,0 +1,209 @@
+package float16
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFromFloat32(t *testing.T) {
+	tests := []struct {
+		name string
+		f    float32
+		want Float16
+	}{
+		{name: "Zero", f: 0, want: 0x0000},
+		{name: "NegZero", f: float32(math.Copysign(0, -1)), want: 0x8000},
+		{name: "One", f: 1, want: 0x3c00},
+		{name: "NegTwo", f: -2, want: 0xc000},
+		{name: "Max", f: 65504, want: Max},
+		{name: "BelowMaxHalfway", f: 65519, want: Max},
+		{name: "MaxHalfwayToInf", f: 65520, want: Inf},
+		{name: "Overflow", f: 1e6, want: Inf},
+		{name: "NegOverflow", f: -1e6, want: NegInf},
+		{name: "TieToEvenDown", f: 1 + 1.0/(1<<11), want: 0x3c00},
+		{name: "TieToEvenUp", f: 1 + 3.0/(1<<11), want: 0x3c02},
+		{name: "AboveTie", f: 1 + 1.0/(1<<11) + 1.0/(1<<20), want: 0x3c01},
+		{name: "BelowTie", f: 1 + 1.0/(1<<11) - 1.0/(1<<20), want: 0x3c00},
+		{name: "CarryIntoExponent", f: 2 - 1.0/(1<<12), want: 0x4000},
+		{name: "SmallestNormal", f: 1.0 / (1 << 14), want: 0x0400},
+		{name: "LargestSubnormal", f: 1.0/(1<<14) - 1.0/(1<<24), want: 0x03ff},
+		{name: "SubnormalCarryIntoNormal", f: 1.0/(1<<14) - 1.0/(1<<25), want: 0x0400},
+		{name: "SmallestSubnormal", f: 1.0 / (1 << 24), want: SmallestNonzero},
+		{name: "NegSmallestSubnormal", f: -1.0 / (1 << 24), want: 0x8001},
+		{name: "SubnormalTieToEvenDown", f: 1.0 / (1 << 25), want: 0x0000},
+		{name: "SubnormalTieToEvenUp", f: 3.0 / (1 << 25), want: 0x0002},
+		{name: "SubnormalAboveTie", f: 1.0/(1<<25) + 1.0/(1<<35), want: SmallestNonzero},
+		{name: "Underflow", f: 1e-10, want: 0x0000},
+		{name: "NegUnderflow", f: -1e-10, want: 0x8000},
+		{name: "Inf", f: float32(math.Inf(1)), want: Inf},
+		{name: "NegInf", f: float32(math.Inf(-1)), want: NegInf},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FromFloat32(tc.f); got != tc.want {
+				t.Errorf("expected %#04x, got: %#04x", tc.want.Bits(), got.Bits())
+			}
+		})
+	}
+}
+
+func TestFromFloat32NaN(t *testing.T) {
+	for _, f := range []float32{float32(math.NaN()), -float32(math.NaN()), math.Float32frombits(0x7f800001)} {
+		if got := FromFloat32(f); !got.IsNaN() {
+			t.Errorf("expected NaN for %#08x, got: %#04x", math.Float32bits(f), got.Bits())
+		}
+	}
+}
+
+func TestFloat32(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Float16
+		want float32
+	}{
+		{name: "Zero", f: 0x0000, want: 0},
+		{name: "One", f: 0x3c00, want: 1},
+		{name: "NegTwo", f: 0xc000, want: -2},
+		{name: "Max", f: Max, want: 65504},
+		{name: "SmallestNormal", f: 0x0400, want: 1.0 / (1 << 14)},
+		{name: "LargestSubnormal", f: 0x03ff, want: 1023.0 / (1 << 24)},
+		{name: "SmallestSubnormal", f: SmallestNonzero, want: 1.0 / (1 << 24)},
+		{name: "NegSubnormal", f: 0x8200, want: -512.0 / (1 << 24)},
+		{name: "Inf", f: Inf, want: float32(math.Inf(1))},
+		{name: "NegInf", f: NegInf, want: float32(math.Inf(-1))},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.f.Float32(); got != tc.want {
+				t.Errorf("expected %v, got: %v", tc.want, got)
+			}
+		})
+	}
+
+	if f := Float16(0x8000).Float32(); f != 0 || !math.Signbit(float64(f)) {
+		t.Errorf("expected negative zero, got: %v", f)
+	}
+
+	if f := NaN.Float32(); !math.IsNaN(float64(f)) {
+		t.Errorf("expected NaN, got: %v", f)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for b := 0; b <= math.MaxUint16; b++ {
+		f := FromBits(uint16(b))
+		if f.IsNaN() {
+			if !FromFloat32(f.Float32()).IsNaN() {
+				t.Errorf("expected NaN %#04x to round trip to NaN", b)
+			}
+			continue
+		}
+
+		if got := FromFloat32(f.Float32()); got != f {
+			t.Errorf("expected %#04x to round trip, got: %#04x", b, got.Bits())
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		f      Float16
+		nan    bool
+		inf    bool
+		posInf bool
+		negInf bool
+	}{
+		{name: "Zero", f: 0},
+		{name: "Max", f: Max},
+		{name: "Inf", f: Inf, inf: true, posInf: true},
+		{name: "NegInf", f: NegInf, inf: true, negInf: true},
+		{name: "NaN", f: NaN, nan: true},
+		{name: "NegNaN", f: NaN.Neg(), nan: true},
+		{name: "SignalingNaN", f: 0x7c01, nan: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.f.IsNaN(); got != tc.nan {
+				t.Errorf("expected IsNaN %v, got: %v", tc.nan, got)
+			}
+
+			if got := tc.f.IsInf(0); got != tc.inf {
+				t.Errorf("expected IsInf(0) %v, got: %v", tc.inf, got)
+			}
+
+			if got := tc.f.IsInf(1); got != tc.posInf {
+				t.Errorf("expected IsInf(1) %v, got: %v", tc.posInf, got)
+			}
+
+			if got := tc.f.IsInf(-1); got != tc.negInf {
+				t.Errorf("expected IsInf(-1) %v, got: %v", tc.negInf, got)
+			}
+		})
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	one, two, three := FromFloat32(1), FromFloat32(2), FromFloat32(3)
+
+	tests := []struct {
+		name string
+		got  Float16
+		want Float16
+	}{
+		{name: "Add", got: one.Add(two), want: three},
+		{name: "Sub", got: one.Sub(three), want: two.Neg()},
+		{name: "Mul", got: two.Mul(three), want: FromFloat32(6)},
+		{name: "Div", got: three.Div(two), want: FromFloat32(1.5)},
+		{name: "DivZero", got: one.Div(0), want: Inf},
+		{name: "AddOverflow", got: Max.Add(Max), want: Inf},
+		{name: "Abs", got: three.Neg().Abs(), want: three},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Errorf("expected %v, got: %v", tc.want, tc.got)
+			}
+		})
+	}
+
+	if !one.Less(two) || two.Less(one) || NaN.Less(one) || one.Less(NaN) {
+		t.Errorf("unexpected Less result")
+	}
+
+	if !Float16(0).Div(0).IsNaN() {
+		t.Errorf("expected 0/0 to be NaN")
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	s := []Float16{0, FromFloat32(1), FromFloat32(-2.5), Inf, SmallestNonzero}
+
+	data := Encode(s)
+	if len(data) != 2*len(s) {
+		t.Fatalf("expected %d bytes, got: %d", 2*len(s), len(data))
+	}
+
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatalf("failed to decode data: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("expected %v, got: %v", s, got)
+	}
+
+	if _, err := Decode(data[:3]); err == nil {
+		t.Errorf("expected error decoding odd length data")
+	}
+
+	f32 := []float32{0, 1, -2.5, 65504}
+	if got := ToFloat32s(FromFloat32s(f32)); !reflect.DeepEqual(got, f32) {
+		t.Errorf("expected %v, got: %v", f32, got)
+	}
+}
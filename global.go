@@ -6,7 +6,11 @@ package ncs
 */
 import "C"
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -100,34 +104,294 @@ func (s Status) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler interface.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Error implements error interface.
+// This allows to match the errors returned by this package against Status values via errors.Is.
+func (s Status) Error() string {
+	return s.String()
+}
+
+// Every error returned by this package, except for context errors, wraps exactly one of the following
+// errors, so the errors can be matched against them via errors.Is.
+var (
+	// ErrBusy is returned when the device is busy.
+	ErrBusy error = StatusBusy
+	// ErrUnexpected is returned when an unexpected error was encountered during the API call.
+	ErrUnexpected error = StatusError
+	// ErrOutOfMemory is returned when the host is out of memory.
+	ErrOutOfMemory error = StatusOutOfMemory
+	// ErrDeviceNotFound is returned when no device has been found at the given index or name.
+	ErrDeviceNotFound error = StatusDeviceNotFound
+	// ErrInvalidParameters is returned when at least one of the given parameters is wrong.
+	ErrInvalidParameters error = StatusInvalidParameters
+	// ErrTimeout is returned when the communication with the device timed out.
+	ErrTimeout error = StatusTimeout
+	// ErrCmdNotFound is returned when the file to boot the device was not found.
+	ErrCmdNotFound error = StatusCmdNotFound
+	// ErrNotAllocated is returned when the graph or FIFO has not been allocated.
+	ErrNotAllocated error = StatusNotAllocated
+	// ErrUnauthorized is returned when an unauthorized operation has been attempted.
+	ErrUnauthorized error = StatusUnauthorized
+	// ErrUnsupportedGraphFile is returned when the graph file version is not supported.
+	ErrUnsupportedGraphFile error = StatusUnsupportedGraphFile
+	// ErrUnsupportedConfigFile is reserved for future use.
+	ErrUnsupportedConfigFile error = StatusUnsupportedConfigFile
+	// ErrUnsupportedFeature is returned when the operation used a feature unsupported by the firmware or by this package.
+	ErrUnsupportedFeature error = StatusUnsupportedFeature
+	// ErrMyriadError is returned when an error has been reported by the device.
+	ErrMyriadError error = StatusMyriadError
+	// ErrInvalidDataLength is returned when the length of the data is invalid.
+	ErrInvalidDataLength error = StatusInvalidDataLength
+	// ErrInvalidHandle is returned when an invalid handle has been used.
+	ErrInvalidHandle error = StatusInvalidHandle
+)
+
+// ErrClosed is returned when using a device, graph or FIFO handle which has already been closed or destroyed.
+// It wraps ErrInvalidHandle.
+var ErrClosed = fmt.Errorf("Handle closed or destroyed: %w", ErrInvalidHandle)
+
+// ErrStaleHandle is returned when using a graph or FIFO handle whose device has been closed or destroyed
+// since the handle was allocated. Stale handles must be destroyed and allocated again. It wraps ErrInvalidHandle.
+var ErrStaleHandle = fmt.Errorf("Handle stale: device closed or reset since allocation: %w", ErrInvalidHandle)
+
+// ErrCallAbandoned is returned when NCSDK call has not returned before its timeout expired.
+// The call keeps running in the background and may still succeed, so it is never retried. It wraps ErrTimeout.
+var ErrCallAbandoned = fmt.Errorf("Call abandoned: still running in the background: %w", ErrTimeout)
+
+// ErrUnsupportedOption is returned when querying an option which is not supported by the device firmware.
+// It wraps ErrUnsupportedFeature.
+var ErrUnsupportedOption = fmt.Errorf("Option not supported by firmware: %w", ErrUnsupportedFeature)
+
+// Error is an error returned when NCSDK API call fails.
+// It wraps the Status returned by the failed API call.
+type Error struct {
+	// Op is the name of the NCSDK API operation which failed
+	Op string
+	// Resource is the type of the resource handle the operation was performed on
+	Resource string
+	// Name is the name of the graph or FIFO the operation was performed on, if it has any
+	Name string
+	// Index is the index of the device the operation was performed on or -1 if it is not known
+	Index int
+	// Status is the NCSDK API status code returned by the failed operation
+	Status Status
+	// DebugInfo contains device debug information when the operation failed with StatusMyriadError
+	DebugInfo string
+}
+
+// errorCodes is non-zero if the operation codes are prepended to the error messages
+var errorCodes int32
+
+// SetErrorCodes enables or disables prepending the operation code returned by Error.Code to the messages of all errors
+// returned by failed NCSDK API calls. This allows log aggregation to group failures by operation. It is disabled by default.
+func SetErrorCodes(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&errorCodes, v)
+}
+
+// Code returns stable machine readable code of the failed operation consisting of space separated key=value pairs,
+// e.g. op=GraphQueueInference idx=0 graph=SSDMobilenet
+// The device index and the resource name are omitted if they are not known.
+func (e *Error) Code() string {
+	var b strings.Builder
+
+	b.WriteString("op=")
+	if e.Resource != "" {
+		b.WriteString(strings.ToUpper(e.Resource[:1]) + e.Resource[1:])
+	}
+	b.WriteString(e.Op)
+
+	if e.Index >= 0 {
+		fmt.Fprintf(&b, " idx=%d", e.Index)
+	}
+
+	if e.Name != "" {
+		fmt.Fprintf(&b, " %s=%s", e.Resource, e.Name)
+	}
+
+	return b.String()
+}
+
+// Error implements error interface
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s %s failed: %s", e.Resource, e.Op, e.Status)
+	if e.DebugInfo != "" {
+		msg += ": " + e.DebugInfo
+	}
+
+	if atomic.LoadInt32(&errorCodes) != 0 {
+		msg = e.Code() + ": " + msg
+	}
+
+	return msg
+}
+
+// Unwrap returns the Status returned by the failed operation
+func (e *Error) Unwrap() error {
+	return e.Status
+}
+
+// StateError is returned when an operation is called on a device, graph or FIFO handle
+// which is not in the state required by the operation, e.g. when allocating a graph on a device
+// which has not been opened or when writing to a FIFO which has not been allocated.
+type StateError struct {
+	// Op is the name of the operation which has been refused
+	Op string
+	// Resource is the type of the resource handle in the invalid state
+	Resource string
+	// State is the current state of the resource handle
+	State fmt.Stringer
+}
+
+// Error implements error interface
+func (e *StateError) Error() string {
+	return fmt.Sprintf("%s %s failed: invalid %s state %s", e.Resource, e.Op, e.Resource, e.State)
+}
+
+// Unwrap returns ErrNotAllocated if the resource has not been allocated yet, otherwise it returns ErrUnauthorized
+func (e *StateError) Unwrap() error {
+	switch e.State {
+	case GraphCreated, FifoCreated:
+		return ErrNotAllocated
+	}
+
+	return ErrUnauthorized
+}
+
+// invalidHandle returns new Error for operation op called with nil resource handle
+func invalidHandle(op, resource string) *Error {
+	return &Error{Op: op, Resource: resource, Index: -1, Status: StatusInvalidHandle}
+}
+
+// invalidParams returns new Error for operation op called with invalid parameters
+func invalidParams(op, resource string) *Error {
+	return &Error{Op: op, Resource: resource, Index: -1, Status: StatusInvalidParameters}
+}
+
+// newError returns new Error for operation op performed on the given resource
+func newError(op, resource string, s C.int) *Error {
+	return &Error{
+		Op:       op,
+		Resource: resource,
+		Index:    -1,
+		Status:   Status(s),
+	}
+}
+
 // Option is NCS option
 type Option interface {
 	// Value returns Option value as its integer code
 	Value() int
 	// Decode decodes raw byte slice option data as returned from NCS
-	Decode([]byte, int) (interface{}, error)
+	Decode([]byte) (interface{}, error)
 }
 
 // TensorDesc describes NCS graph inputs and outputs
 type TensorDesc struct {
 	// BatchSize contains number of elements.
-	BatchSize uint
+	BatchSize uint `json:"batch_size"`
 	// Channels contains number of channels (when dealing with digital images).
-	Channels uint
+	Channels uint `json:"channels"`
 	// Width is data width (i.e. number of matrix columns).
-	Width uint
+	Width uint `json:"width"`
 	// Height is data height (i.e. number of matrix rows).
-	Height uint
+	Height uint `json:"height"`
 	// Size is the total data size in the tensor.
-	Size uint
+	Size uint `json:"size"`
 	// CStride is channel stride (Stride in the channels' dimension).
-	CStride uint
+	CStride uint `json:"c_stride"`
 	// WStride is width stride (Stride in the horizontal dimension).
-	WStride uint
+	WStride uint `json:"w_stride"`
 	// HStride is height stride (Stride in the vertical dimension).
-	HStride uint
+	HStride uint `json:"h_stride"`
 	// DataType is data type of the tensor.
-	DataType FifoDataType
+	DataType FifoDataType `json:"data_type"`
+}
+
+// NewTensorDesc returns new tensor descriptor of n tensors with c channels, height h, width w and data type dt.
+// The strides are set to the NCSDK default non-strided channel minor layout.
+func NewTensorDesc(n, c, h, w uint, dt FifoDataType) *TensorDesc {
+	td := &TensorDesc{
+		BatchSize: n,
+		Channels:  c,
+		Width:     w,
+		Height:    h,
+		DataType:  dt,
+	}
+
+	td.CStride = td.BytesPerElem()
+	td.WStride = c * td.CStride
+	td.HStride = w * td.WStride
+	td.Size = td.ExpectedSize()
+
+	return td
+}
+
+// ElemCount returns the number of elements in the tensor
+func (td *TensorDesc) ElemCount() uint {
+	return td.BatchSize * td.Channels * td.Height * td.Width
+}
+
+// BytesPerElem returns the size of a single tensor element in bytes.
+// It returns 0 if the tensor data type is unknown.
+func (td *TensorDesc) BytesPerElem() uint {
+	switch td.DataType {
+	case FifoFP16:
+		return 2
+	case FifoFP32:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// ExpectedSize returns the total size of the tensor data in bytes computed from its shape and strides
+func (td *TensorDesc) ExpectedSize() uint {
+	return td.BatchSize * td.Height * td.HStride
+}
+
+// Validate checks if the tensor descriptor is consistent: all the dimensions must be positive,
+// the data type must be known, the strides must be large enough to fit the lower dimensions
+// and the total size must match the size computed from the shape and strides.
+// It returns error describing the first inconsistency it finds.
+func (td *TensorDesc) Validate() error {
+	if td.BatchSize == 0 || td.Channels == 0 || td.Height == 0 || td.Width == 0 {
+		return fmt.Errorf("Invalid tensor shape %dx%dx%dx%d: dimensions must not be zero: %w",
+			td.BatchSize, td.Channels, td.Height, td.Width, ErrInvalidParameters)
+	}
+
+	bpe := td.BytesPerElem()
+	if bpe == 0 {
+		return fmt.Errorf("Invalid tensor data type %d: %w", td.DataType, ErrInvalidParameters)
+	}
+
+	if td.CStride < bpe {
+		return fmt.Errorf("Invalid tensor channel stride %d: expected at least %d: %w", td.CStride, bpe, ErrInvalidParameters)
+	}
+
+	if td.WStride < td.Channels*td.CStride {
+		return fmt.Errorf("Invalid tensor width stride %d: expected at least %d: %w",
+			td.WStride, td.Channels*td.CStride, ErrInvalidParameters)
+	}
+
+	if td.HStride < td.Width*td.WStride {
+		return fmt.Errorf("Invalid tensor height stride %d: expected at least %d: %w",
+			td.HStride, td.Width*td.WStride, ErrInvalidParameters)
+	}
+
+	if td.Size != td.ExpectedSize() {
+		return fmt.Errorf("Invalid tensor size %d: expected %d: %w", td.Size, td.ExpectedSize(), ErrInvalidParameters)
+	}
+
+	return nil
 }
 
 // Tensor is graph tensor as returned from NCS
@@ -136,32 +400,195 @@ type Tensor struct {
 	Data []byte
 	// MetaData contains tensor metadata
 	MetaData interface{}
+	// off is the offset of the data which has not been read yet
+	off int
+	// alloc is the allocator of Data, nil if Data is allocated on the Go heap
+	alloc Allocator
+}
+
+// tensorDescSize is the size of the raw tensor descriptor in bytes
+const tensorDescSize = C.sizeof_struct_ncTensorDescriptor_t
+
+// checkDataLen checks if data is long enough to contain count option elements of size bytes.
+// It returns error describing the mismatch if it's not.
+func checkDataLen(opt Option, data []byte, size, count int) error {
+	if count < 1 {
+		return fmt.Errorf("Invalid %v element count: %d: %w", opt, count, ErrInvalidParameters)
+	}
+
+	if len(data) < size*count {
+		return fmt.Errorf("Invalid %v data length: expected at least %d bytes for %d element(s), got %d: %w",
+			opt, size*count, count, len(data), ErrInvalidDataLength)
+	}
+
+	return nil
+}
+
+// elemCount returns the number of option elements of size bytes contained in data.
+// It returns 1 if data is shorter than a single element so that the length checks report the mismatch.
+func elemCount(data []byte, size int) int {
+	if count := len(data) / size; count > 1 {
+		return count
+	}
+
+	return 1
+}
+
+// decodeString decodes NUL terminated string from raw option data
+func decodeString(data []byte) string {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		data = data[:i]
+	}
+
+	return string(data)
+}
+
+// decodeTensorDescs decodes all the tensor descriptors contained in raw option data
+func decodeTensorDescs(opt Option, data []byte) ([]TensorDesc, error) {
+	count := elemCount(data, tensorDescSize)
+	if err := checkDataLen(opt, data, tensorDescSize, count); err != nil {
+		return nil, err
+	}
+
+	vals := make([]struct {
+		BatchSize uint32
+		Channels  uint32
+		Width     uint32
+		Height    uint32
+		Size      uint32
+		CStride   uint32
+		WStride   uint32
+		HStride   uint32
+		DataType  int32
+	}, count)
+
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &vals); err != nil {
+		return nil, err
+	}
+
+	tensorDescs := make([]TensorDesc, count)
+	for i, val := range vals {
+		tensorDescs[i] = TensorDesc{
+			BatchSize: uint(val.BatchSize),
+			Channels:  uint(val.Channels),
+			Width:     uint(val.Width),
+			Height:    uint(val.Height),
+			Size:      uint(val.Size),
+			CStride:   uint(val.CStride),
+			WStride:   uint(val.WStride),
+			HStride:   uint(val.HStride),
+			DataType:  FifoDataType(val.DataType),
+		}
+	}
+
+	return tensorDescs, nil
 }
 
 // getOption is a function which unifies querying of various NCS resource options
 func getOption(resource string, handle unsafe.Pointer, option Option, size uint) ([]byte, error) {
+	data, _, s, err := readOption(resource, handle, option, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if Status(s) != StatusOK {
+		return nil, newError("GetOption", resource, s)
+	}
+
+	return data, nil
+}
+
+// queryOption queries the option of the resource handle without knowing its size in advance.
+// It first queries the option with a buffer of size bytes, which is enough for all the scalar options.
+// If NCSDK reports the buffer length is invalid it queries the option again with a buffer of the length
+// reported by NCSDK. It returns the status of the last NCSDK call so the caller can build the handle error,
+// or error if the resource is unknown.
+func queryOption(resource string, handle unsafe.Pointer, option Option, size uint) ([]byte, C.int, error) {
+	data, dataLen, s, err := readOption(resource, handle, option, size)
+	if err != nil {
+		return nil, s, err
+	}
+
+	if Status(s) == StatusInvalidDataLength && dataLen > 0 && dataLen != size {
+		data, _, s, err = readOption(resource, handle, option, dataLen)
+	}
+
+	return data, s, err
+}
+
+// readOption reads the option of the resource handle into a buffer of size bytes.
+// It returns the option data along with the data length reported by NCSDK and the NCSDK status code.
+// The data is only returned if the status is StatusOK.
+func readOption(resource string, handle unsafe.Pointer, option Option, size uint) ([]byte, uint, C.int, error) {
+	if size == 0 {
+		size = 1
+	}
+
+	data := make([]byte, size)
+	dataLen, s, err := getOptionFunc(resource, handle, option.Value(), data)
+	if err != nil {
+		return nil, 0, C.int(s), err
+	}
+
+	if s != StatusOK {
+		return nil, dataLen, C.int(s), nil
+	}
+
+	// NCSDK may report less data than the buffer can hold
+	if dataLen > size {
+		dataLen = size
+	}
+
+	return data[:dataLen], dataLen, C.int(s), nil
+}
+
+// getOptionFunc reads the option opt of the resource handle into data.
+// It returns the data length reported by NCSDK along with the NCSDK status code,
+// or error if the resource is unknown. It's a variable so that NCSDK can be faked in tests.
+var getOptionFunc = func(resource string, handle unsafe.Pointer, opt int, data []byte) (uint, Status, error) {
 	// allocate buffer for options data
-	data := C.malloc(C.sizeof_char * C.ulong(size))
-	defer C.free(unsafe.Pointer(data))
-	dataLen := C.uint(size)
+	buf := C.malloc(C.sizeof_char * C.ulong(len(data)))
+	defer C.free(unsafe.Pointer(buf))
+	dataLen := C.uint(len(data))
 
 	// NCCS API status code
 	var s C.int
 
 	switch resource {
+	case "global":
+		s = C.ncs_GlobalGetOption(C.int(opt), buf, &dataLen)
 	case "device":
-		s = C.ncs_DeviceGetOption(handle, C.int(option.Value()), data, &dataLen)
+		s = C.ncs_DeviceGetOption(handle, C.int(opt), buf, &dataLen)
 	case "graph":
-		s = C.ncs_GraphGetOption(handle, C.int(option.Value()), data, &dataLen)
+		s = C.ncs_GraphGetOption(handle, C.int(opt), buf, &dataLen)
 	case "fifo":
-		s = C.ncs_FifoGetOption(handle, C.int(option.Value()), data, &dataLen)
+		s = C.ncs_FifoGetOption(handle, C.int(opt), buf, &dataLen)
 	default:
-		return nil, fmt.Errorf("Unknown resource: %s", resource)
+		return 0, Status(s), fmt.Errorf("Unknown resource: %s: %w", resource, ErrInvalidParameters)
 	}
 
-	if Status(s) != StatusOK {
-		return nil, fmt.Errorf("Failed to get %s option: %s", resource, Status(s))
+	if Status(s) == StatusOK {
+		n := len(data)
+		if int(dataLen) < n {
+			n = int(dataLen)
+		}
+		copy(data, C.GoBytes(unsafe.Pointer(buf), C.int(n)))
+	}
+
+	return uint(dataLen), Status(s), nil
+}
+
+// debugInfo queries the debug information option opt of the resource handle and returns it.
+// It returns empty string if it fails to query the debug information.
+func debugInfo(resource string, handle unsafe.Pointer, opt Option) string {
+	if handle == nil {
+		return ""
+	}
+
+	data, err := getOption(resource, handle, opt, DebugBufferSize)
+	if err != nil {
+		return ""
 	}
 
-	return C.GoBytes(unsafe.Pointer(data), C.int(size)), nil
+	return decodeString(data)
 }
@@ -0,0 +1,369 @@
+package ncs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+// int32Size is the size of the C int, unsigned int and float option elements in bytes
+const int32Size = 4
+
+// tensorDescRawSize is the size of the raw C tensor descriptor in bytes
+const tensorDescRawSize = 9 * int32Size
+
+// decodeTest is a single Option.Decode test case
+type decodeTest struct {
+	name string
+	data []byte
+	want interface{}
+	err  error
+}
+
+// encode encodes vals into little endian raw option data
+func encode(t testing.TB, vals ...interface{}) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	for _, val := range vals {
+		if err := binary.Write(&buf, binary.LittleEndian, val); err != nil {
+			t.Fatalf("failed to encode %v: %v", val, err)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// seq returns n sequential uint32 values starting with 1
+func seq(n int) []uint32 {
+	vals := make([]uint32, n)
+	for i := range vals {
+		vals[i] = uint32(i + 1)
+	}
+
+	return vals
+}
+
+// testDecode runs tests against opt Decode
+func testDecode(t *testing.T, opt Option, tests []decodeTest) {
+	t.Helper()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := opt.Decode(tc.data)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expected error %v, got: %v", tc.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to decode %v: %v", opt, err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %#v, got: %#v", tc.want, got)
+			}
+		})
+	}
+}
+
+// scalarDecodeTests returns decode tests for options whose value is a single unsigned integer
+func scalarDecodeTests(t *testing.T) []decodeTest {
+	return []decodeTest{
+		{name: "Empty", data: nil, err: ErrInvalidDataLength},
+		{name: "Short", data: []byte{1, 0, 0}, err: ErrInvalidDataLength},
+		{name: "Exact", data: encode(t, uint32(7)), want: uint(7)},
+		{name: "Multi", data: encode(t, []uint32{7, 8}), want: uint(7)},
+	}
+}
+
+// stringDecodeTests returns decode tests for NUL terminated string options
+func stringDecodeTests() []decodeTest {
+	return []decodeTest{
+		{name: "Empty", data: nil, want: ""},
+		{name: "Short", data: []byte("ncs"), want: "ncs"},
+		{name: "Exact", data: []byte("ncs\x00"), want: "ncs"},
+		{name: "Multi", data: []byte("ncs\x00dev\x00"), want: "ncs"},
+	}
+}
+
+// tensorDescDecodeTests returns decode tests for tensor descriptor options.
+// If multi is true, the options decode all descriptors in data, otherwise only the first one.
+func tensorDescDecodeTests(t *testing.T, multi bool) []decodeTest {
+	raw := func(i uint32) []uint32 {
+		return []uint32{1, 3, 224 + i, 224, 224 * 224 * 3, 2, 6, 6 * 224, uint32(FifoFP32)}
+	}
+
+	desc := func(i uint) TensorDesc {
+		return TensorDesc{
+			BatchSize: 1,
+			Channels:  3,
+			Width:     224 + i,
+			Height:    224,
+			Size:      224 * 224 * 3,
+			CStride:   2,
+			WStride:   6,
+			HStride:   6 * 224,
+			DataType:  FifoFP32,
+		}
+	}
+
+	exact := encode(t, raw(0))
+	multiData := encode(t, raw(0), raw(1))
+
+	tests := []decodeTest{
+		{name: "Empty", data: nil, err: ErrInvalidDataLength},
+		{name: "Short", data: exact[:tensorDescRawSize-1], err: ErrInvalidDataLength},
+	}
+
+	if multi {
+		return append(tests,
+			decodeTest{name: "Exact", data: exact, want: []TensorDesc{desc(0)}},
+			decodeTest{name: "Multi", data: multiData, want: []TensorDesc{desc(0), desc(1)}},
+		)
+	}
+
+	d := desc(0)
+	return append(tests,
+		decodeTest{name: "Exact", data: exact, want: &d},
+		decodeTest{name: "Multi", data: multiData, want: &d},
+	)
+}
+
+func TestCheckDataLen(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []byte
+		size  int
+		count int
+		err   error
+	}{
+		{name: "Empty", data: nil, size: int32Size, count: 1, err: ErrInvalidDataLength},
+		{name: "Short", data: make([]byte, int32Size-1), size: int32Size, count: 1, err: ErrInvalidDataLength},
+		{name: "ShortArray", data: make([]byte, 3*int32Size), size: int32Size, count: 4, err: ErrInvalidDataLength},
+		{name: "Exact", data: make([]byte, int32Size), size: int32Size, count: 1},
+		{name: "ExactArray", data: make([]byte, 4*int32Size), size: int32Size, count: 4},
+		{name: "Multi", data: make([]byte, 5*int32Size+1), size: int32Size, count: 4},
+		{name: "ZeroCount", data: make([]byte, int32Size), size: int32Size, count: 0, err: ErrInvalidParameters},
+		{name: "NegativeCount", data: make([]byte, int32Size), size: int32Size, count: -1, err: ErrInvalidParameters},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkDataLen(RODeviceState, tc.data, tc.size, tc.count)
+			if tc.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error %v, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestElemCount(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "Empty", data: nil, want: 1},
+		{name: "Short", data: make([]byte, int32Size-1), want: 1},
+		{name: "Exact", data: make([]byte, int32Size), want: 1},
+		{name: "Multi", data: make([]byte, 3*int32Size+1), want: 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := elemCount(tc.data, int32Size); got != tc.want {
+				t.Errorf("expected %d, got: %d", tc.want, got)
+			}
+		})
+	}
+}
+
+// optionCall is a single faked NCSDK option query
+type optionCall struct {
+	// dataLen is the data length reported by NCSDK
+	dataLen uint
+	// status is the status returned by NCSDK
+	status Status
+}
+
+// fakeGetOption replaces getOptionFunc with a fake which replays calls and fills the data with 0xff.
+// It returns a pointer to the sizes of the buffers the fake was called with.
+func fakeGetOption(t *testing.T, calls ...optionCall) *[]int {
+	t.Helper()
+
+	orig := getOptionFunc
+	t.Cleanup(func() { getOptionFunc = orig })
+
+	var sizes []int
+	getOptionFunc = func(resource string, handle unsafe.Pointer, opt int, data []byte) (uint, Status, error) {
+		if len(sizes) == len(calls) {
+			t.Fatalf("unexpected option query %d with %d bytes", len(sizes)+1, len(data))
+		}
+		call := calls[len(sizes)]
+		sizes = append(sizes, len(data))
+
+		if call.status == StatusOK {
+			for i := 0; i < len(data) && uint(i) < call.dataLen; i++ {
+				data[i] = 0xff
+			}
+		}
+
+		return call.dataLen, call.status, nil
+	}
+
+	return &sizes
+}
+
+func TestQueryOption(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   uint
+		calls  []optionCall
+		sizes  []int
+		status Status
+		want   int
+	}{
+		{
+			name:   "OK",
+			size:   4,
+			calls:  []optionCall{{dataLen: 4, status: StatusOK}},
+			sizes:  []int{4},
+			status: StatusOK,
+			want:   4,
+		},
+		{
+			name:   "OKShortData",
+			size:   8,
+			calls:  []optionCall{{dataLen: 3, status: StatusOK}},
+			sizes:  []int{8},
+			status: StatusOK,
+			want:   3,
+		},
+		{
+			name:   "OKLongDataLen",
+			size:   4,
+			calls:  []optionCall{{dataLen: 16, status: StatusOK}},
+			sizes:  []int{4},
+			status: StatusOK,
+			want:   4,
+		},
+		{
+			name: "InvalidDataLength",
+			size: 4,
+			calls: []optionCall{
+				{dataLen: 36, status: StatusInvalidDataLength},
+				{dataLen: 36, status: StatusOK},
+			},
+			sizes:  []int{4, 36},
+			status: StatusOK,
+			want:   36,
+		},
+		{
+			name: "InvalidDataLengthTwice",
+			size: 4,
+			calls: []optionCall{
+				{dataLen: 36, status: StatusInvalidDataLength},
+				{dataLen: 72, status: StatusInvalidDataLength},
+			},
+			sizes:  []int{4, 36},
+			status: StatusInvalidDataLength,
+		},
+		{
+			name:   "InvalidDataLengthSameSize",
+			size:   4,
+			calls:  []optionCall{{dataLen: 4, status: StatusInvalidDataLength}},
+			sizes:  []int{4},
+			status: StatusInvalidDataLength,
+		},
+		{
+			name:   "InvalidDataLengthZero",
+			size:   4,
+			calls:  []optionCall{{dataLen: 0, status: StatusInvalidDataLength}},
+			sizes:  []int{4},
+			status: StatusInvalidDataLength,
+		},
+		{
+			name:   "ZeroSize",
+			size:   0,
+			calls:  []optionCall{{dataLen: 1, status: StatusOK}},
+			sizes:  []int{1},
+			status: StatusOK,
+			want:   1,
+		},
+		{
+			name:   "Error",
+			size:   4,
+			calls:  []optionCall{{dataLen: 4, status: StatusInvalidHandle}},
+			sizes:  []int{4},
+			status: StatusInvalidHandle,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sizes := fakeGetOption(t, tc.calls...)
+
+			data, s, err := queryOption("device", nil, RODeviceState, tc.size)
+			if err != nil {
+				t.Fatalf("failed to query option: %v", err)
+			}
+
+			if !reflect.DeepEqual(*sizes, tc.sizes) {
+				t.Errorf("expected queries with %v bytes, got: %v", tc.sizes, *sizes)
+			}
+
+			if Status(s) != tc.status {
+				t.Fatalf("expected status %v, got: %v", tc.status, Status(s))
+			}
+
+			if tc.status != StatusOK {
+				if data != nil {
+					t.Errorf("expected no data, got: %v", data)
+				}
+				return
+			}
+
+			if want := bytes.Repeat([]byte{0xff}, tc.want); !bytes.Equal(data, want) {
+				t.Errorf("expected %v, got: %v", want, data)
+			}
+		})
+	}
+}
+
+func TestReadOptionUnknownResource(t *testing.T) {
+	if _, _, _, err := readOption("unknown", nil, RODeviceState, 4); !errors.Is(err, ErrInvalidParameters) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidParameters, err)
+	}
+}
+
+func TestGetOption(t *testing.T) {
+	fakeGetOption(t, optionCall{dataLen: 4, status: StatusOK})
+
+	data, err := getOption("device", nil, RODeviceState, 4)
+	if err != nil {
+		t.Fatalf("failed to get option: %v", err)
+	}
+
+	if want := bytes.Repeat([]byte{0xff}, 4); !bytes.Equal(data, want) {
+		t.Errorf("expected %v, got: %v", want, data)
+	}
+
+	fakeGetOption(t, optionCall{dataLen: 4, status: StatusInvalidHandle})
+
+	if _, err := getOption("device", nil, RODeviceState, 4); !errors.Is(err, ErrInvalidHandle) {
+		t.Errorf("expected error %v, got: %v", ErrInvalidHandle, err)
+	}
+}
@@ -0,0 +1,207 @@
+package ncs
+
+// #cgo LDFLAGS: -lmvnc
+/*
+#include <ncs.h>
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+)
+
+// LogLevel is NCSDK logging level
+//
+// For more information:
+// https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncLogLevel_t.html
+type LogLevel int
+
+const (
+	// LogDebug logs debug and all other messages
+	LogDebug LogLevel = iota
+	// LogInfo logs info, warning, error and fatal messages
+	LogInfo
+	// LogWarn logs warning, error and fatal messages
+	LogWarn
+	// LogError logs error and fatal messages
+	LogError
+	// LogFatal logs fatal messages only
+	LogFatal
+)
+
+// String implements fmt.Stringer interface
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "LOG_DEBUG"
+	case LogInfo:
+		return "LOG_INFO"
+	case LogWarn:
+		return "LOG_WARN"
+	case LogError:
+		return "LOG_ERROR"
+	case LogFatal:
+		return "LOG_FATAL"
+	default:
+		return "LOG_UNKNOWN_LEVEL"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler interface
+func (l LogLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// GlobalOption is NCSDK global option which configures the NCSDK library itself.
+// The options starting with RW are both gettable and settable.
+// The options starting with RO are only gettable.
+//
+// For more information:
+// https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGlobalOption_t.html
+type GlobalOption int
+
+const (
+	// RWGlobalLogLevel configures the NCSDK logging level to one of LogLevel values
+	RWGlobalLogLevel GlobalOption = iota
+	// ROGlobalAPIVersion queries the version of the linked NCSDK API library
+	ROGlobalAPIVersion
+)
+
+// globalOptSize is a map which maps global options to their native sizes
+var globalOptSize = map[Option]uint{
+	RWGlobalLogLevel:   C.sizeof_int,
+	ROGlobalAPIVersion: C.sizeof_uint,
+}
+
+// String implements fmt.Stringer interface
+func (gl GlobalOption) String() string {
+	switch gl {
+	case RWGlobalLogLevel:
+		return "RW_LOG_LEVEL"
+	case ROGlobalAPIVersion:
+		return "RO_API_VERSION"
+	default:
+		return "GLOBAL_UNKNOWN_OPTION"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler interface
+func (gl GlobalOption) MarshalText() ([]byte, error) {
+	return []byte(gl.String()), nil
+}
+
+// Value returns option value as integer
+func (gl GlobalOption) Value() int {
+	return int(gl)
+}
+
+// Decode decodes options data encoded in raw bytes and returns it in its native type.
+// RWGlobalLogLevel is decoded into LogLevel and ROGlobalAPIVersion into []uint32.
+// It returns error if the data is too short or if it fails to be decoded into the option native type.
+func (gl GlobalOption) Decode(data []byte) (interface{}, error) {
+	buf := bytes.NewReader(data)
+
+	switch gl {
+	case RWGlobalLogLevel:
+
+		if err := checkDataLen(gl, data, C.sizeof_int, 1); err != nil {
+			return nil, err
+		}
+
+		var val uint32
+		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
+			return nil, err
+		}
+
+		return LogLevel(val), nil
+
+	case ROGlobalAPIVersion:
+
+		if err := checkDataLen(gl, data, C.sizeof_uint, VersionMaxSize); err != nil {
+			return nil, err
+		}
+
+		var val [VersionMaxSize]uint32
+		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
+			return nil, err
+		}
+
+		return val[:], nil
+
+	default:
+		return nil, fmt.Errorf("Unable to decode global option data: %s: %w", gl, ErrInvalidParameters)
+	}
+}
+
+// GetGlobalOption queries the value of NCSDK global option and returns it encoded in a byte slice.
+// It returns error if it fails to retrieve the option value.
+//
+// For more information:
+// https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGlobalGetOption.html
+func GetGlobalOption(opt GlobalOption) ([]byte, error) {
+	size, ok := globalOptSize[opt]
+	if !ok {
+		return nil, fmt.Errorf("Unknown global option %d: %w", opt, ErrInvalidParameters)
+	}
+
+	if opt == ROGlobalAPIVersion {
+		size *= VersionMaxSize
+	}
+
+	return getOption("global", nil, opt, size)
+}
+
+// SetGlobalOption sets the value of NCSDK global option to data, which must contain the option
+// value encoded in its native type, i.e. the same way the value is returned by GetGlobalOption.
+// It returns error if the option is read only or if it fails to set the option.
+//
+// For more information:
+// https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGlobalSetOption.html
+func SetGlobalOption(opt GlobalOption, data []byte) error {
+	if opt != RWGlobalLogLevel {
+		return fmt.Errorf("Option %s is read only: %w", opt, ErrInvalidParameters)
+	}
+
+	if len(data) != int(globalOptSize[opt]) {
+		return fmt.Errorf("Invalid %s value: expected %d bytes, got %d: %w", opt, globalOptSize[opt], len(data), ErrInvalidParameters)
+	}
+
+	s := C.ncs_GlobalSetOption(C.int(opt), unsafe.Pointer(&data[0]), C.uint(len(data)))
+
+	if Status(s) != StatusOK {
+		return newError("SetOption", "global", s)
+	}
+
+	return nil
+}
+
+// GetLogLevel returns the current NCSDK logging level.
+// It returns error if it fails to query the log level.
+func GetLogLevel() (LogLevel, error) {
+	data, err := GetGlobalOption(RWGlobalLogLevel)
+	if err != nil {
+		return 0, err
+	}
+
+	val, err := RWGlobalLogLevel.Decode(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return val.(LogLevel), nil
+}
+
+// SetLogLevel sets the NCSDK logging level to level, e.g. SetLogLevel(LogDebug) turns on mvnc debug logging.
+// It returns error if level is not a valid log level or if it fails to set the log level.
+func SetLogLevel(level LogLevel) error {
+	if level < LogDebug || level > LogFatal {
+		return fmt.Errorf("Invalid log level %d: %w", level, ErrInvalidParameters)
+	}
+
+	data := make([]byte, globalOptSize[RWGlobalLogLevel])
+	binary.LittleEndian.PutUint32(data, uint32(level))
+
+	return SetGlobalOption(RWGlobalLogLevel, data)
+}
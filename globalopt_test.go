@@ -0,0 +1,42 @@
+package ncs
+
+import "testing"
+
+func TestGlobalOptionDecode(t *testing.T) {
+	t.Run(RWGlobalLogLevel.String(), func(t *testing.T) {
+		testDecode(t, RWGlobalLogLevel, []decodeTest{
+			{name: "Empty", data: nil, err: ErrInvalidDataLength},
+			{name: "Short", data: []byte{1, 0, 0}, err: ErrInvalidDataLength},
+			{name: "Exact", data: encode(t, uint32(LogWarn)), want: LogWarn},
+			{name: "Multi", data: encode(t, []uint32{uint32(LogWarn), uint32(LogError)}), want: LogWarn},
+		})
+	})
+
+	t.Run(ROGlobalAPIVersion.String(), func(t *testing.T) {
+		testDecode(t, ROGlobalAPIVersion, []decodeTest{
+			{name: "Empty", data: nil, err: ErrInvalidDataLength},
+			{name: "Short", data: encode(t, seq(VersionMaxSize-1)), err: ErrInvalidDataLength},
+			{name: "Exact", data: encode(t, seq(VersionMaxSize)), want: seq(VersionMaxSize)},
+			{name: "Multi", data: encode(t, seq(2*VersionMaxSize)), want: seq(VersionMaxSize)},
+		})
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		testDecode(t, GlobalOption(-1), []decodeTest{
+			{name: "Exact", data: encode(t, uint32(1)), err: ErrInvalidParameters},
+		})
+	})
+}
+
+func FuzzGlobalOptionDecode(f *testing.F) {
+	for opt := RWGlobalLogLevel; opt <= ROGlobalAPIVersion; opt++ {
+		f.Add(int(opt), []byte(nil))
+		f.Add(int(opt), make([]byte, int32Size))
+		f.Add(int(opt), make([]byte, VersionMaxSize*int32Size))
+	}
+
+	f.Fuzz(func(t *testing.T, opt int, data []byte) {
+		// any error is fine as long as decoding malformed data never panics
+		_, _ = GlobalOption(opt).Decode(data)
+	})
+}
@@ -7,8 +7,11 @@ package ncs
 import "C"
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
+	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -42,6 +45,11 @@ func (gs GraphState) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler interface.
+func (gs GraphState) MarshalText() ([]byte, error) {
+	return []byte(gs.String()), nil
+}
+
 // GraphOption defines graph options
 // The options starting with RW are both gettable and settable
 // The options starting with RO are only gettable
@@ -122,6 +130,11 @@ func (g GraphOption) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler interface.
+func (g GraphOption) MarshalText() ([]byte, error) {
+	return []byte(g.String()), nil
+}
+
 // Value returns option value as integer
 func (g GraphOption) Value() int {
 	return int(g)
@@ -129,9 +142,9 @@ func (g GraphOption) Value() int {
 
 // Decode decodes options data encoded in raw bytes and returns it in its native type.
 // The returned data then can be asserted into its native type.
-// If the data contains more than one element you need to specify the number of expected elements via count.
-// It returns error if the data fails to be decoded into the option native type.
-func (g GraphOption) Decode(data []byte, count int) (interface{}, error) {
+// The number of elements of array options is derived from the length of the data.
+// It returns error if the data is too short or if it fails to be decoded into the option native type.
+func (g GraphOption) Decode(data []byte) (interface{}, error) {
 	buf := bytes.NewReader(data)
 
 	switch g {
@@ -142,6 +155,10 @@ func (g GraphOption) Decode(data []byte, count int) (interface{}, error) {
 		RWGraphExecutorsCount,
 		ROGraphInferenceTimeSize:
 
+		if err := checkDataLen(g, data, C.sizeof_int, 1); err != nil {
+			return nil, err
+		}
+
 		var val uint32
 		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
 			return nil, err
@@ -151,15 +168,25 @@ func (g GraphOption) Decode(data []byte, count int) (interface{}, error) {
 		return uint(val), nil
 
 	case ROGraphInferenceTime:
+
+		count := elemCount(data, C.sizeof_float)
+		if err := checkDataLen(g, data, C.sizeof_float, count); err != nil {
+			return nil, err
+		}
+
 		val := make([]float32, count)
 		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
 			return nil, err
 		}
 
-		return val[:], nil
+		return val, nil
 
 	case ROGraphVersion:
 
+		if err := checkDataLen(g, data, C.sizeof_uint, 2); err != nil {
+			return nil, err
+		}
+
 		var val [2]uint32
 		if err := binary.Read(buf, binary.LittleEndian, &val); err != nil {
 			return nil, err
@@ -170,64 +197,48 @@ func (g GraphOption) Decode(data []byte, count int) (interface{}, error) {
 	case ROGraphDebugInfo,
 		ROGraphName:
 
-		return string(data), nil
+		return decodeString(data), nil
 
 	case ROGraphInputTensorDesc,
 		ROGraphOutputTensorDesc:
-		vals := make([]struct {
-			BatchSize uint32
-			Channels  uint32
-			Width     uint32
-			Height    uint32
-			Size      uint32
-			CStride   uint32
-			WStride   uint32
-			HStride   uint32
-			DataType  int32
-		}, count)
-
-		if err := binary.Read(buf, binary.LittleEndian, &vals); err != nil {
-			return nil, err
-		}
-
-		tensorDescs := make([]TensorDesc, count)
-		for i, val := range vals {
-			td := TensorDesc{
-				BatchSize: uint(val.BatchSize),
-				Channels:  uint(val.Channels),
-				Width:     uint(val.Width),
-				Height:    uint(val.Height),
-				Size:      uint(val.Size),
-				CStride:   uint(val.CStride),
-				WStride:   uint(val.WStride),
-				HStride:   uint(val.HStride),
-				DataType:  FifoDataType(val.DataType),
-			}
-			tensorDescs[i] = td
-		}
 
-		return tensorDescs[:], nil
+		return decodeTensorDescs(g, data)
 
 	default:
-		return nil, fmt.Errorf("Unable to decode graph option data: %s", g)
+		return nil, fmt.Errorf("Unable to decode graph option data: %s: %w", g, ErrInvalidParameters)
 	}
-
-	return nil, nil
 }
 
 // Graph is NCSDK neural network graph
 type Graph struct {
-	name   string
-	handle unsafe.Pointer
-	device *Device
+	// mu guards the handle and graph state
+	mu      sync.RWMutex
+	name    string
+	handle  unsafe.Pointer
+	device  *Device
+	devGen  uint64
+	state   GraphState
+	retry   *RetryPolicy
+	timeout time.Duration
+	logger  Logger
+	strict  bool
+	alloc   Allocator
+	// calls pins the graph while its calls run in the background
+	calls pin
 }
 
 // NewGraph creates new Graph with given name and returns it
-// It returns error if it fails to create new graph
+// The graph can be configured via opts.
+// It returns error if any of opts is not supported by graphs or if it fails to create new graph
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGraphCreate.html
-func NewGraph(name string) (*Graph, error) {
+func NewGraph(name string, opts ...Opt) (*Graph, error) {
+	o, err := newHandleOpts("graph", opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	var handle unsafe.Pointer
 
 	_name := C.CString(name)
@@ -236,10 +247,16 @@ func NewGraph(name string) (*Graph, error) {
 	s := C.ncs_GraphCreate(_name, &handle)
 
 	if Status(s) != StatusOK {
-		return nil, fmt.Errorf("Failed to create new graph: %s", Status(s))
+		err := newError("Create", "graph", s)
+		err.Name = name
+		return nil, err
 	}
 
-	return &Graph{name: name, handle: handle}, nil
+	g := &Graph{name: name, handle: handle, retry: o.retry, timeout: o.timeout, logger: o.logger, alloc: o.alloc}
+	trackHandle(g, "graph", name)
+	logf(g.logger, "graph %s: created", name)
+
+	return g, nil
 }
 
 // Allocate allocates a graph on NCS device. This function sends graphData to NCS device. It does not allocate input or output FIFO queues. You have to either allocate them separately or use either AllocateWithFifosDefault() or AllocateWithFifosOpts() functions whcih conveniently create and allocate the FIFO queues.
@@ -248,23 +265,73 @@ func NewGraph(name string) (*Graph, error) {
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGraphAllocate.html
 func (g *Graph) Allocate(d *Device, graphData []byte) error {
-	s := C.ncs_GraphAllocate(d.handle, g.handle, unsafe.Pointer(&graphData[0]), C.uint(len(graphData)))
+	return g.AllocateContext(context.Background(), d, graphData)
+}
+
+// AllocateContext allocates a graph on NCS device.
+// It returns ctx error if ctx is done before the graph is allocated. See Allocate for more details.
+// If ctx is done, graphData must not be modified as the allocation may still be reading it in the background.
+func (g *Graph) AllocateContext(ctx context.Context, d *Device, graphData []byte) error {
+	if g == nil || d == nil {
+		return invalidHandle("Allocate", "graph")
+	}
+
+	if len(graphData) == 0 {
+		return invalidParams("Allocate", "graph")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if g.handle == nil || d.handle == nil {
+		return ErrClosed
+	}
+
+	if g.state != GraphCreated {
+		return &StateError{Op: "Allocate", Resource: "graph", State: g.state}
+	}
+	if d.state != DeviceOpened {
+		return &StateError{Op: "Allocate", Resource: "device", State: d.state}
+	}
+
+	handle, devHandle := g.handle, d.handle
+	s, err := g.retry.do(ctx, func() (C.int, error) {
+		return monitor(ctx, 0, func() C.int {
+			return C.ncs_GraphAllocate(devHandle, handle, unsafe.Pointer(&graphData[0]), C.uint(len(graphData)))
+		}, &g.calls, &d.calls)
+	})
+
+	if err != nil {
+		return err
+	}
 
 	if Status(s) != StatusOK {
-		return fmt.Errorf("Failed to allocate new graph: %s", Status(s))
+		return g.newError("Allocate", s)
 	}
 
 	g.device = d
+	g.devGen = d.generation()
+	g.state = GraphAllocated
+	logf(g.logger, "graph %s: allocated", g.name)
 
 	return nil
 }
 
 // AllocateWithFifosDefault allocates a graph and creates and allocates FIFO queues with default parameters for inference. Both FIFOs have FifoDataType set to FifoFP32. Inbound FIFO queue is initialized with FifoHostWO type and outbound FIFO queue with FifoHostRO type. It returns FifoQueue or error if it fails to allocate the graph.
+// Both FIFOs can hold 2 elements unless NCS_FIFO_DEPTH environment variable is set to a different number of elements.
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGraphAllocateWithFifos.html
 func (g *Graph) AllocateWithFifosDefault(d *Device, graphData []byte) (*FifoQueue, error) {
-	return g.AllocateWithFifosOpts(d, graphData, &FifoOpts{FifoHostWO, FifoFP32, 2}, &FifoOpts{FifoHostRO, FifoFP32, 2})
+	depth, err := envFifoDepth()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.AllocateWithFifosOpts(d, graphData, &FifoOpts{FifoHostWO, FifoFP32, depth}, &FifoOpts{FifoHostRO, FifoFP32, depth})
 }
 
 // AllocateWithFifosOpts allocates a graph and creates and allocates FIFO queues for inference. This function is similar to AllocateWithFifosDefault, but rather than initializing FIFOs with default values it accepts parameters that allow to specify FIFO queue parameters
@@ -272,22 +339,81 @@ func (g *Graph) AllocateWithFifosDefault(d *Device, graphData []byte) (*FifoQueu
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGraphAllocateWithFifosEx.html
 func (g *Graph) AllocateWithFifosOpts(d *Device, graphData []byte, inOpts *FifoOpts, outOpts *FifoOpts) (*FifoQueue, error) {
+	return g.AllocateWithFifosOptsContext(context.Background(), d, graphData, inOpts, outOpts)
+}
+
+// AllocateWithFifosOptsContext allocates a graph and creates and allocates FIFO queues for inference.
+// It returns ctx error if ctx is done before the graph is allocated. See AllocateWithFifosOpts for more details.
+// If ctx is done, graphData must not be modified as the allocation may still be reading it in the background.
+func (g *Graph) AllocateWithFifosOptsContext(ctx context.Context, d *Device, graphData []byte, inOpts *FifoOpts, outOpts *FifoOpts) (*FifoQueue, error) {
+	if g == nil || d == nil {
+		return nil, invalidHandle("AllocateWithFifos", "graph")
+	}
+
+	if len(graphData) == 0 || inOpts == nil || outOpts == nil {
+		return nil, invalidParams("AllocateWithFifos", "graph")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if g.handle == nil || d.handle == nil {
+		return nil, ErrClosed
+	}
+
+	if g.state != GraphCreated {
+		return nil, &StateError{Op: "AllocateWithFifos", Resource: "graph", State: g.state}
+	}
+	if d.state != DeviceOpened {
+		return nil, &StateError{Op: "AllocateWithFifos", Resource: "device", State: d.state}
+	}
+
 	var inHandle, outHandle unsafe.Pointer
 
-	s := C.ncs_GraphAllocateWithFifosEx(d.handle,
-		g.handle, unsafe.Pointer(&graphData[0]), C.uint(len(graphData)),
-		&inHandle, C.ncFifoType(inOpts.Type), C.int(inOpts.NumElem), C.ncFifoDataType(inOpts.DataType),
-		&outHandle, C.ncFifoType(outOpts.Type), C.int(outOpts.NumElem), C.ncFifoDataType(outOpts.DataType))
+	handle, devHandle := g.handle, d.handle
+	s, err := g.retry.do(ctx, func() (C.int, error) {
+		// in and out are only read once the monitored call has returned
+		var in, out unsafe.Pointer
+
+		s, err := monitor(ctx, 0, func() C.int {
+			return C.ncs_GraphAllocateWithFifosEx(devHandle,
+				handle, unsafe.Pointer(&graphData[0]), C.uint(len(graphData)),
+				&in, C.ncFifoType(inOpts.Type), C.int(inOpts.NumElem), C.ncFifoDataType(inOpts.DataType),
+				&out, C.ncFifoType(outOpts.Type), C.int(outOpts.NumElem), C.ncFifoDataType(outOpts.DataType))
+		}, &g.calls, &d.calls)
+
+		if err == nil && Status(s) == StatusOK {
+			inHandle, outHandle = in, out
+		}
+
+		return s, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
 
 	if Status(s) != StatusOK {
-		return nil, fmt.Errorf("Failed to allocate graph with FIFOs: %s", Status(s))
+		return nil, g.newError("AllocateWithFifos", s)
 	}
 
 	g.device = d
+	g.devGen = d.generation()
+	g.state = GraphAllocated
+	logf(g.logger, "graph %s: allocated", g.name)
+
+	in := &Fifo{typ: inOpts.Type, state: FifoAllocated, handle: inHandle, device: d, devGen: g.devGen, retry: g.retry, timeout: g.timeout, logger: g.logger, alloc: g.alloc}
+	trackHandle(in, "fifo", "")
+
+	out := &Fifo{typ: outOpts.Type, state: FifoAllocated, handle: outHandle, device: d, devGen: g.devGen, retry: g.retry, timeout: g.timeout, logger: g.logger, alloc: g.alloc}
+	trackHandle(out, "fifo", "")
 
 	return &FifoQueue{
-		In:  &Fifo{handle: inHandle, device: d},
-		Out: &Fifo{handle: outHandle, device: d},
+		In:  in,
+		Out: out,
 	}, nil
 }
 
@@ -297,51 +423,260 @@ func (g *Graph) AllocateWithFifosOpts(d *Device, graphData []byte, inOpts *FifoO
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGraphQueueInference.html
 func (g *Graph) QueueInference(f *FifoQueue) error {
-	s := C.ncs_GraphQueueInference(g.handle, &f.In.handle, C.uint(1), &f.Out.handle, C.uint(1))
+	return g.QueueInferenceContext(context.Background(), f)
+}
+
+// QueueInferenceContext queues data for inference to be processed by a graph with specified input and output FIFOs
+// It returns ctx error if ctx is done before the inference is queued. See QueueInference for more details.
+func (g *Graph) QueueInferenceContext(ctx context.Context, f *FifoQueue) error {
+	if g == nil || !f.valid() {
+		return invalidHandle("QueueInference", "graph")
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	f.rlock()
+	defer f.runlock()
+
+	if g.handle == nil || f.In.handle == nil || f.Out.handle == nil {
+		return ErrClosed
+	}
+
+	if g.state != GraphAllocated {
+		return &StateError{Op: "QueueInference", Resource: "graph", State: g.state}
+	}
+	if f.In.state != FifoAllocated {
+		return &StateError{Op: "QueueInference", Resource: "fifo", State: f.In.state}
+	}
+	if f.Out.state != FifoAllocated {
+		return &StateError{Op: "QueueInference", Resource: "fifo", State: f.Out.state}
+	}
+
+	if g.stale() || f.In.stale() || f.Out.stale() {
+		return ErrStaleHandle
+	}
+
+	handle, in, out := g.handle, f.In.handle, f.Out.handle
+	s, err := g.retry.do(ctx, func() (C.int, error) {
+		return monitor(ctx, callTimeout(g.timeout), func() C.int {
+			in, out := in, out
+			return C.ncs_GraphQueueInference(handle, &in, C.uint(1), &out, C.uint(1))
+		}, &g.calls, &f.In.calls, &f.Out.calls, devicePin(g.device))
+	})
+
+	if err != nil {
+		return err
+	}
 
 	if Status(s) != StatusOK {
-		return fmt.Errorf("Failed to queue inference: %s", Status(s))
+		return g.newError("QueueInference", s)
 	}
 
 	return nil
 }
 
 // QueueInferenceWithFifoElem writes an element to a FIFO, usually an input tensor for inference, and queues an inference to be processed by a graph. This is a convenient way to write an input tensor and queue an inference in one call
+// The metadata is kept on the host and returned in the Tensor read from the outbound FIFO.
 // If it fails to queue the data tensor it returns error
+// If strict mode is enabled it returns error without queueing the data if its size does not match the inbound FIFO element size.
+// If the call times out, data must not be modified as the call may still be reading it in the background.
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGraphQueueInferenceWithFifoElem.html
 func (g *Graph) QueueInferenceWithFifoElem(f *FifoQueue, data []byte, metaData interface{}) error {
-	dataLen := C.uint(len(data))
+	return g.QueueInferenceWithFifoElemContext(context.Background(), f, data, metaData)
+}
+
+// QueueInferenceWithFifoElemContext writes an element to a FIFO and queues an inference to be processed by a graph.
+// It returns ctx error if ctx is done before the inference is queued. See QueueInferenceWithFifoElem for more details.
+// If ctx is done, data must not be modified as the call may still be reading it in the background.
+func (g *Graph) QueueInferenceWithFifoElemContext(ctx context.Context, f *FifoQueue, data []byte, metaData interface{}) error {
+	if g == nil || !f.valid() {
+		return invalidHandle("QueueInferenceWithFifoElem", "graph")
+	}
+
+	if len(data) == 0 {
+		return invalidParams("QueueInferenceWithFifoElem", "graph")
+	}
 
-	s := C.ncs_GraphQueueInferenceWithFifoElem(g.handle, f.In.handle, f.Out.handle, unsafe.Pointer(&data[0]), &dataLen, unsafe.Pointer(&metaData))
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	f.rlock()
+	defer f.runlock()
+
+	if g.handle == nil || f.In.handle == nil || f.Out.handle == nil {
+		return ErrClosed
+	}
+
+	if g.state != GraphAllocated {
+		return &StateError{Op: "QueueInferenceWithFifoElem", Resource: "graph", State: g.state}
+	}
+	if f.In.state != FifoAllocated {
+		return &StateError{Op: "QueueInferenceWithFifoElem", Resource: "fifo", State: f.In.state}
+	}
+	if f.Out.state != FifoAllocated {
+		return &StateError{Op: "QueueInferenceWithFifoElem", Resource: "fifo", State: f.Out.state}
+	}
+
+	if g.stale() || f.In.stale() || f.Out.stale() {
+		return ErrStaleHandle
+	}
+
+	if g.strict {
+		if err := checkInputSize(f.In, data); err != nil {
+			return err
+		}
+	}
+
+	handle, in, out := g.handle, f.In.handle, f.Out.handle
+	s, err := g.retry.do(ctx, func() (C.int, error) {
+		if err := ctx.Err(); err != nil {
+			return C.int(StatusOK), err
+		}
+
+		// every attempt carries its own token, so the elements written by an abandoned attempt
+		// and by a subsequent retry never share the same token
+		token := newMetaToken(metaData, g.device, f.In, f.Out)
+
+		s, err := monitor(ctx, callTimeout(g.timeout), func() C.int {
+			dataLen := C.uint(len(data))
+			return C.ncs_GraphQueueInferenceWithFifoElem(handle, in, out, unsafe.Pointer(&data[0]), &dataLen, token)
+		}, &g.calls, &f.In.calls, &f.Out.calls, devicePin(g.device))
+
+		// abandoned call might still succeed in the background, so its token can't be released
+		if err == nil && Status(s) != StatusOK {
+			releaseMetaToken(token)
+		}
+
+		return s, err
+	})
+
+	if err != nil {
+		return err
+	}
 
 	if Status(s) != StatusOK {
-		return fmt.Errorf("Failed to queue inference: %s", Status(s))
+		return g.newError("QueueInferenceWithFifoElem", s)
 	}
 
 	return nil
 }
 
+// SetTimeout sets the timeout of the potentially blocking graph calls.
+// FIFO queues allocated along with the graph inherit the timeout.
+// Zero duration means the package default timeout is used. See SetDefaultTimeout for more details.
+func (g *Graph) SetTimeout(t time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.timeout = t
+}
+
+// SetStrict enables or disables strict mode.
+// In strict mode QueueInferenceWithFifoElem verifies the size of the queued data matches the size
+// of the inbound FIFO element before sending it to the device. This costs an extra option query per call,
+// but it turns opaque NCSDK failures caused by malformed input into actionable errors.
+func (g *Graph) SetStrict(strict bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.strict = strict
+}
+
+// SetRetryPolicy sets the policy used to retry the graph calls which fail with StatusBusy or StatusTimeout.
+// FIFO queues allocated along with the graph inherit the policy. Passing nil disables retries.
+func (g *Graph) SetRetryPolicy(p *RetryPolicy) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.retry = p
+}
+
+// SetAllocator sets the allocator of the element buffers of the FIFO queues allocated along with the graph.
+// It does not affect the queues which have already been allocated. Passing nil means the package default is used.
+func (g *Graph) SetAllocator(a Allocator) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.alloc = a
+}
+
+// Handle returns the underlying NCSDK struct ncGraphHandle_t pointer or nil if the graph has been destroyed.
+//
+// This is an escape hatch for advanced use: it allows calling NCSDK functions which are not wrapped
+// by this package. The returned pointer must not be used after the graph has been destroyed and
+// it must not be passed to ncGraphDestroy; use the Graph methods instead.
+// Changes made to the graph through the returned pointer are not tracked by the Graph.
+func (g *Graph) Handle() unsafe.Pointer {
+	if g == nil {
+		return nil
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.handle
+}
+
+// stale returns true if the graph device has been closed or destroyed since the graph was allocated.
+// It must be called with g.mu held.
+func (g *Graph) stale() bool {
+	return g.device != nil && g.device.generation() != g.devGen
+}
+
+// newError returns new Error for the failed graph operation op.
+// It fetches the graph debug information if the operation failed with StatusMyriadError.
+// It must be called with g.mu held.
+func (g *Graph) newError(op string, s C.int) *Error {
+	err := newError(op, "graph", s)
+	err.Name = g.name
+	if g.device != nil {
+		err.Index = g.device.index
+	}
+
+	if err.Status == StatusMyriadError {
+		err.DebugInfo = debugInfo("graph", g.handle, ROGraphDebugInfo)
+	}
+
+	return err
+}
+
 // GetOption queries the value of an option for a graph and returns it encoded in a byte slice
 // It returns error if it failed to retrieve the option value
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGraphGetOption.html
 func (g *Graph) GetOption(opt GraphOption) ([]byte, error) {
-	if opt == RWGraphExecutorsCount {
-		return nil, fmt.Errorf("Option %s not implemented", opt)
+	if g == nil {
+		return nil, invalidHandle("GetOption", "graph")
 	}
 
-	var data unsafe.Pointer
-	var dataLen C.uint
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 
-	s := C.ncs_GraphGetOption(g.handle, C.int(opt), data, &dataLen)
+	if g.handle == nil {
+		return nil, ErrClosed
+	}
 
-	if Status(s) == StatusInvalidDataLength {
-		return g.GetOptionWithByteSize(opt, graphOptSize[opt]*uint(dataLen))
+	if g.stale() {
+		return nil, ErrStaleHandle
 	}
 
-	return nil, fmt.Errorf("Failed to read %s option: %s", opt, Status(s))
+	if opt == RWGraphExecutorsCount {
+		return nil, fmt.Errorf("Option %s not implemented: %w", opt, ErrUnsupportedFeature)
+	}
+
+	data, s, err := queryOption("graph", g.handle, opt, graphOptSize[opt])
+	if err != nil {
+		return nil, err
+	}
+
+	if Status(s) != StatusOK {
+		return nil, g.newError("GetOption", s)
+	}
+
+	return data, nil
 }
 
 // GetOptionsWithSize queries NCS grapg options and returns it encoded in a byte slice of size elements.
@@ -351,24 +686,188 @@ func (g *Graph) GetOption(opt GraphOption) ([]byte, error) {
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGraphGetOption.html
 func (g *Graph) GetOptionWithByteSize(opt GraphOption, size uint) ([]byte, error) {
+	if g == nil {
+		return nil, invalidHandle("GetOption", "graph")
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if g.handle == nil {
+		return nil, ErrClosed
+	}
+
+	if g.stale() {
+		return nil, ErrStaleHandle
+	}
+
 	if opt == RWGraphExecutorsCount {
-		return nil, fmt.Errorf("Option %s not implemented", opt)
+		return nil, fmt.Errorf("Option %s not implemented: %w", opt, ErrUnsupportedFeature)
 	}
 
 	return getOption("graph", g.handle, opt, size)
 }
 
+// State returns the state of the graph as reported by NCSDK.
+// It returns error if it fails to query the option.
+func (g *Graph) State() (GraphState, error) {
+	val, err := GetOption[uint](g, ROGraphState)
+
+	return GraphState(val), err
+}
+
+// InputTensorDescs returns the descriptors of the graph input tensors in order.
+// It returns error if it fails to query the option.
+func (g *Graph) InputTensorDescs() ([]TensorDesc, error) {
+	return GetOption[[]TensorDesc](g, ROGraphInputTensorDesc)
+}
+
+// OutputTensorDescs returns the descriptors of the graph output tensors in order.
+// It returns error if it fails to query the option.
+func (g *Graph) OutputTensorDescs() ([]TensorDesc, error) {
+	return GetOption[[]TensorDesc](g, ROGraphOutputTensorDesc)
+}
+
+// InferenceTimes returns the times taken by each graph layer during the last inference in milliseconds.
+// It returns error if it fails to query the option.
+func (g *Graph) InferenceTimes() ([]float32, error) {
+	return GetOption[[]float32](g, ROGraphInferenceTime)
+}
+
+// Version returns the version of the graph file format.
+// It returns error if it fails to query the option.
+func (g *Graph) Version() (VersionNumber, error) {
+	val, err := GetOption[[]uint32](g, ROGraphVersion)
+	if err != nil {
+		return VersionNumber{}, err
+	}
+
+	return newVersionNumber(val), nil
+}
+
+// GraphInfo aggregates the information about NCS graph
+type GraphInfo struct {
+	// Name is the name of the graph
+	Name string `json:"name"`
+	// State is the graph state as reported by NCSDK
+	State GraphState `json:"state"`
+	// Version is the version of the graph file format
+	Version VersionNumber `json:"version"`
+	// InputCount is the number of graph inputs
+	InputCount uint `json:"input_count"`
+	// OutputCount is the number of graph outputs
+	OutputCount uint `json:"output_count"`
+	// Inputs are the descriptors of the graph input tensors in order
+	Inputs []TensorDesc `json:"inputs"`
+	// Outputs are the descriptors of the graph output tensors in order
+	Outputs []TensorDesc `json:"outputs"`
+}
+
+// Info queries all the graph information options at once and returns them aggregated in GraphInfo.
+// The graph must be allocated before its information can be queried.
+// It returns error if it fails to query any of the options.
+func (g *Graph) Info() (*GraphInfo, error) {
+	if g == nil {
+		return nil, invalidHandle("GetOption", "graph")
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if g.handle == nil {
+		return nil, ErrClosed
+	}
+
+	if g.stale() {
+		return nil, ErrStaleHandle
+	}
+
+	query := func(opt GraphOption) (interface{}, error) {
+		data, s, err := queryOption("graph", g.handle, opt, graphOptSize[opt])
+		if err != nil {
+			return nil, err
+		}
+
+		if Status(s) != StatusOK {
+			return nil, g.newError("GetOption", s)
+		}
+
+		return opt.Decode(data)
+	}
+
+	info := &GraphInfo{Name: g.name}
+
+	val, err := query(ROGraphState)
+	if err != nil {
+		return nil, err
+	}
+	info.State = GraphState(val.(uint))
+
+	if val, err = query(ROGraphVersion); err != nil {
+		return nil, err
+	}
+	info.Version = newVersionNumber(val.([]uint32))
+
+	if val, err = query(ROGraphInputCount); err != nil {
+		return nil, err
+	}
+	info.InputCount = val.(uint)
+
+	if val, err = query(ROGraphOutputCount); err != nil {
+		return nil, err
+	}
+	info.OutputCount = val.(uint)
+
+	if val, err = query(ROGraphInputTensorDesc); err != nil {
+		return nil, err
+	}
+	info.Inputs = val.([]TensorDesc)
+
+	if val, err = query(ROGraphOutputTensorDesc); err != nil {
+		return nil, err
+	}
+	info.Outputs = val.([]TensorDesc)
+
+	return info, nil
+}
+
 // Destroy destroys NCS graph handle and frees associated resources.
 // This function must be called for every graph that was initialized with NewGraph().
+// It returns ErrClosed if the graph has already been destroyed.
+// It blocks until the abandoned calls which use the handle have returned.
 //
 // For more information:
 // https://movidius.github.io/ncsdk/ncapi/ncapi2/c_api/ncGraphDestroy.html
 func (g *Graph) Destroy() error {
+	if g == nil {
+		return invalidHandle("Destroy", "graph")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.handle == nil {
+		return ErrClosed
+	}
+
+	// the background calls must return before the handle is freed
+	g.calls.wait(context.Background())
+
 	s := C.ncs_GraphDestroy(&g.handle)
 
 	if Status(s) != StatusOK {
-		return fmt.Errorf("Failed to destroy graph: %s", Status(s))
+		return g.newError("Destroy", s)
 	}
 
+	g.handle = nil
+	untrackHandle(g)
+	logf(g.logger, "graph %s: destroyed", g.name)
+
 	return nil
 }
+
+// Close destroys the graph handle. It implements io.Closer interface, so the graph can be
+// released along with other resources by generic resource management code. See Destroy for more details.
+func (g *Graph) Close() error {
+	return g.Destroy()
+}
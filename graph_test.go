@@ -0,0 +1,70 @@
+package ncs
+
+import "testing"
+
+func TestGraphOptionDecode(t *testing.T) {
+	scalar := []GraphOption{
+		ROGraphState,
+		ROGraphInputCount,
+		ROGraphOutputCount,
+		ROGraphOptionClassLimit,
+		RWGraphExecutorsCount,
+		ROGraphInferenceTimeSize,
+	}
+
+	for _, opt := range scalar {
+		t.Run(opt.String(), func(t *testing.T) {
+			testDecode(t, opt, scalarDecodeTests(t))
+		})
+	}
+
+	for _, opt := range []GraphOption{ROGraphDebugInfo, ROGraphName} {
+		t.Run(opt.String(), func(t *testing.T) {
+			testDecode(t, opt, stringDecodeTests())
+		})
+	}
+
+	for _, opt := range []GraphOption{ROGraphInputTensorDesc, ROGraphOutputTensorDesc} {
+		t.Run(opt.String(), func(t *testing.T) {
+			testDecode(t, opt, tensorDescDecodeTests(t, true))
+		})
+	}
+
+	t.Run(ROGraphInferenceTime.String(), func(t *testing.T) {
+		times := []float32{1.5, 2.25, 0.125}
+		testDecode(t, ROGraphInferenceTime, []decodeTest{
+			{name: "Empty", data: nil, err: ErrInvalidDataLength},
+			{name: "Short", data: []byte{1, 0, 0}, err: ErrInvalidDataLength},
+			{name: "Exact", data: encode(t, times[:1]), want: times[:1]},
+			{name: "Multi", data: append(encode(t, times), 0), want: times},
+		})
+	})
+
+	t.Run(ROGraphVersion.String(), func(t *testing.T) {
+		testDecode(t, ROGraphVersion, []decodeTest{
+			{name: "Empty", data: nil, err: ErrInvalidDataLength},
+			{name: "Short", data: encode(t, seq(1)), err: ErrInvalidDataLength},
+			{name: "Exact", data: encode(t, seq(2)), want: seq(2)},
+			{name: "Multi", data: encode(t, seq(4)), want: seq(2)},
+		})
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		testDecode(t, GraphOption(-1), []decodeTest{
+			{name: "Exact", data: encode(t, uint32(1)), err: ErrInvalidParameters},
+		})
+	})
+}
+
+func FuzzGraphOptionDecode(f *testing.F) {
+	for opt := ROGraphState; opt <= ROGraphInferenceTimeSize; opt++ {
+		f.Add(int(opt), []byte(nil))
+		f.Add(int(opt), make([]byte, int32Size))
+		f.Add(int(opt), make([]byte, 2*tensorDescRawSize))
+	}
+
+	f.Fuzz(func(t *testing.T, opt int, data []byte) {
+		// any error is fine as long as decoding malformed data never panics
+		_, _ = GraphOption(opt).Decode(data)
+	})
+}
@@ -0,0 +1,74 @@
+//go:build ncsdevice
+
+package ncs
+
+import "testing"
+
+// openDevice creates and opens the first Neural Compute Stick and destroys it once the test finishes
+func openDevice(t *testing.T) *Device {
+	t.Helper()
+
+	d, err := NewDevice(0)
+	if err != nil {
+		t.Fatalf("failed to create device, is the stick attached? %v", err)
+	}
+
+	if err := d.Open(); err != nil {
+		d.Destroy()
+		t.Fatalf("failed to open device: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			t.Errorf("failed to close device: %v", err)
+		}
+		if err := d.Destroy(); err != nil {
+			t.Errorf("failed to destroy device: %v", err)
+		}
+	})
+
+	return d
+}
+
+func TestDeviceHardware(t *testing.T) {
+	d := openDevice(t)
+
+	info, err := d.Info()
+	if err != nil {
+		t.Fatalf("failed to query device info: %v", err)
+	}
+
+	if info.State != DeviceOpened {
+		t.Errorf("expected state %s, got: %s", DeviceOpened, info.State)
+	}
+
+	stats, err := d.ThermalStats()
+	if err != nil {
+		t.Fatalf("failed to query thermal stats: %v", err)
+	}
+
+	if len(stats) != ThermalBufferSize {
+		t.Errorf("expected %d thermal stats, got: %d", ThermalBufferSize, len(stats))
+	}
+}
+
+func TestDevicesHardware(t *testing.T) {
+	devs, err := Devices()
+	if err != nil {
+		t.Fatalf("failed to query devices: %v", err)
+	}
+
+	if len(devs) == 0 {
+		t.Fatalf("no devices found, is the stick attached?")
+	}
+}
+
+func TestGlobalOptionsHardware(t *testing.T) {
+	if _, err := GetLogLevel(); err != nil {
+		t.Errorf("failed to query log level: %v", err)
+	}
+
+	if _, err := GetGlobalOption(ROGlobalAPIVersion); err != nil {
+		t.Errorf("failed to query API version: %v", err)
+	}
+}
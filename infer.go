@@ -0,0 +1,91 @@
+package ncs
+
+import (
+	"context"
+	"fmt"
+)
+
+// Result is the result of an inference along with the metadata of the input which produced it
+type Result[M any] struct {
+	// Data contains raw output tensor data
+	Data []byte
+	// MetaData contains the metadata the input was queued with
+	MetaData M
+	// tensor is the output tensor read from the outbound FIFO
+	tensor *Tensor
+}
+
+// Release returns the result data buffer to the allocator which allocated it and sets Data to nil.
+// The data must not be used after the result has been released. See Tensor.Release for more details.
+func (r *Result[M]) Release() {
+	if r == nil {
+		return
+	}
+
+	r.tensor.Release()
+	r.Data, r.tensor = nil, nil
+}
+
+// Infer queues input along with metadata meta for inference to be processed by graph g with FIFO queue f
+// and waits for the result to be read from the outbound FIFO. The metadata is returned in the result
+// in its native type M, so it does not need to be asserted by the caller.
+// It returns error if it fails to queue the inference, read the result or if the metadata of the read
+// result is not of type M, which happens when the queue is shared with inferences queued with different metadata.
+//
+// For example, the results can be correlated with the images which produced them as follows:
+//
+//	res, err := ncs.Infer(graph, queue, input, imagePath)
+func Infer[M any](g *Graph, f *FifoQueue, input []byte, meta M) (Result[M], error) {
+	return InferContext(context.Background(), g, f, input, meta)
+}
+
+// InferContext queues input along with metadata meta for inference and waits for the result.
+// It returns ctx error if ctx is done before the result is read. See Infer for more details.
+func InferContext[M any](ctx context.Context, g *Graph, f *FifoQueue, input []byte, meta M) (Result[M], error) {
+	var res Result[M]
+
+	if !f.valid() {
+		return res, invalidHandle("Infer", "graph")
+	}
+
+	if err := g.QueueInferenceWithFifoElemContext(ctx, f, input, meta); err != nil {
+		return res, err
+	}
+
+	t, err := f.Out.ReadElemContext(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if t.MetaData != nil {
+		m, ok := t.MetaData.(M)
+		if !ok {
+			t.Release()
+			return res, fmt.Errorf("Invalid result metadata: expected %T, got %T: %w", res.MetaData, t.MetaData, ErrInvalidParameters)
+		}
+		res.MetaData = m
+	}
+
+	res.Data, res.tensor = t.Data, t
+
+	return res, nil
+}
+
+// Infer queues data for inference to be processed by graph g with FIFO queue f and waits for
+// the output tensor to be read from the outbound FIFO. It covers the common case of running
+// a single inference at a time; use the package level Infer to correlate the outputs with their
+// inputs when the queue is shared by multiple goroutines.
+// It returns error if it fails to queue the inference or to read the output tensor.
+func (g *Graph) Infer(f *FifoQueue, data []byte) (*Tensor, error) {
+	return g.InferContext(context.Background(), f, data)
+}
+
+// InferContext queues data for inference and waits for the output tensor.
+// It returns ctx error if ctx is done before the output tensor is read. See Infer for more details.
+func (g *Graph) InferContext(ctx context.Context, f *FifoQueue, data []byte) (*Tensor, error) {
+	if err := g.QueueInferenceWithFifoElemContext(ctx, f, data, nil); err != nil {
+		return nil, err
+	}
+
+	return f.Out.ReadElemContext(ctx)
+}
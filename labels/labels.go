@@ -0,0 +1,81 @@
+// Package labels provides the label sets of the datasets commonly used to train
+// the classification and detection models run on Neural Compute Stick.
+package labels
+
+import (
+	_ "embed"
+	"strings"
+)
+
+var (
+	//go:embed imagenet.txt
+	imagenetData string
+	//go:embed voc.txt
+	vocData string
+	//go:embed coco.txt
+	cocoData string
+)
+
+// Set is a list of labels ordered by their class index
+type Set []string
+
+// Name returns the label of the class with index i or "unknown" if i is out of range
+func (s Set) Name(i int) string {
+	if i < 0 || i >= len(s) {
+		return "unknown"
+	}
+
+	return s[i]
+}
+
+// Index returns the class index of the label name or -1 if the set does not contain it
+func (s Set) Index(name string) int {
+	for i, label := range s {
+		if label == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parse splits the embedded label data into lines
+func parse(data string) Set {
+	return Set(strings.Split(strings.TrimRight(data, "\n"), "\n"))
+}
+
+// ImageNet returns the labels of the 1000 ImageNet classes e.g. "tench, Tinca tinca".
+// The synset identifiers of the classes are returned by ImageNetSynsets.
+func ImageNet() Set {
+	lines := parse(imagenetData)
+	for i, line := range lines {
+		if j := strings.IndexByte(line, ' '); j >= 0 {
+			lines[i] = line[j+1:]
+		}
+	}
+
+	return lines
+}
+
+// ImageNetSynsets returns the WordNet synset identifiers of the 1000 ImageNet classes e.g. "n01440764"
+func ImageNetSynsets() Set {
+	lines := parse(imagenetData)
+	for i, line := range lines {
+		if j := strings.IndexByte(line, ' '); j >= 0 {
+			lines[i] = line[:j]
+		}
+	}
+
+	return lines
+}
+
+// VOC returns the labels of the 20 PASCAL VOC classes preceded by the "background" class,
+// as used by the SSD MobileNet detection models.
+func VOC() Set {
+	return parse(vocData)
+}
+
+// COCO returns the labels of the 80 COCO object detection classes
+func COCO() Set {
+	return parse(cocoData)
+}
@@ -0,0 +1,103 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSets(t *testing.T) {
+	tests := []struct {
+		name  string
+		set   Set
+		size  int
+		first string
+		last  string
+	}{
+		{name: "ImageNet", set: ImageNet(), size: 1000, first: "tench, Tinca tinca", last: "toilet tissue, toilet paper, bathroom tissue"},
+		{name: "ImageNetSynsets", set: ImageNetSynsets(), size: 1000, first: "n01440764", last: "n15075141"},
+		{name: "VOC", set: VOC(), size: 21, first: "background", last: "tvmonitor"},
+		{name: "COCO", set: COCO(), size: 80, first: "person", last: "toothbrush"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.set) != tc.size {
+				t.Fatalf("expected %d labels, got: %d", tc.size, len(tc.set))
+			}
+
+			if tc.set[0] != tc.first {
+				t.Errorf("expected first label %q, got: %q", tc.first, tc.set[0])
+			}
+
+			if last := tc.set[len(tc.set)-1]; last != tc.last {
+				t.Errorf("expected last label %q, got: %q", tc.last, last)
+			}
+
+			for i, label := range tc.set {
+				if label == "" || strings.TrimSpace(label) != label {
+					t.Errorf("invalid label %d: %q", i, label)
+				}
+			}
+		})
+	}
+}
+
+func TestImageNetSplit(t *testing.T) {
+	labels, synsets := ImageNet(), ImageNetSynsets()
+
+	for i := range labels {
+		if !strings.HasPrefix(synsets[i], "n") || len(synsets[i]) != 9 || strings.Contains(synsets[i], " ") {
+			t.Errorf("invalid synset %d: %q", i, synsets[i])
+		}
+
+		if strings.HasPrefix(labels[i], synsets[i]) {
+			t.Errorf("label %d contains its synset: %q", i, labels[i])
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	voc := VOC()
+
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{index: 0, want: "background"},
+		{index: 1, want: "aeroplane"},
+		{index: 20, want: "tvmonitor"},
+		{index: 21, want: "unknown"},
+		{index: -1, want: "unknown"},
+	}
+
+	for _, tc := range tests {
+		if got := voc.Name(tc.index); got != tc.want {
+			t.Errorf("index %d: expected %q, got: %q", tc.index, tc.want, got)
+		}
+	}
+
+	if got := Set(nil).Name(0); got != "unknown" {
+		t.Errorf("expected %q, got: %q", "unknown", got)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	coco := COCO()
+
+	tests := []struct {
+		name string
+		want int
+	}{
+		{name: "person", want: 0},
+		{name: "toothbrush", want: 79},
+		{name: "Person", want: -1},
+		{name: "", want: -1},
+		{name: "unicorn", want: -1},
+	}
+
+	for _, tc := range tests {
+		if got := coco.Index(tc.name); got != tc.want {
+			t.Errorf("label %q: expected %d, got: %d", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package ncs
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// handleTracker tracks the handles which have been created but not destroyed yet
+var handleTracker = struct {
+	sync.Mutex
+	// live contains all the handles which have not been destroyed
+	live map[interface{}]struct{}
+	// leaks contains the handles created while leak detection was enabled
+	leaks   map[interface{}]Leak
+	enabled bool
+}{
+	live:  make(map[interface{}]struct{}),
+	leaks: make(map[interface{}]Leak),
+}
+
+// Leak describes a device, graph or FIFO handle which has not been destroyed.
+type Leak struct {
+	// Resource is the type of the leaked resource handle
+	Resource string
+	// Name is the name of the leaked resource, if it has any
+	Name string
+	// Stack is the stack trace of the goroutine which created the handle
+	Stack string
+}
+
+// SetLeakDetection enables or disables handle leak detection.
+// When enabled, the creation stack trace of every device, graph and FIFO handle is recorded until
+// the handle is destroyed. Capturing stack traces is expensive, so this should only be used for debugging.
+// Disabling leak detection discards all the recorded handles.
+func SetLeakDetection(enabled bool) {
+	handleTracker.Lock()
+	defer handleTracker.Unlock()
+
+	handleTracker.enabled = enabled
+	if !enabled {
+		handleTracker.leaks = make(map[interface{}]Leak)
+	}
+}
+
+// CheckLeaks returns all the handles which have been created since leak detection was enabled
+// and which have not been destroyed yet. It returns empty slice if leak detection is disabled.
+func CheckLeaks() []Leak {
+	handleTracker.Lock()
+	defer handleTracker.Unlock()
+
+	res := make([]Leak, 0, len(handleTracker.leaks))
+	for _, l := range handleTracker.leaks {
+		res = append(res, l)
+	}
+
+	return res
+}
+
+// trackHandle records creation of handle h.
+// If leak detection is enabled it also records the stack trace of the calling goroutine.
+func trackHandle(h interface{}, resource, name string) {
+	handleTracker.Lock()
+	defer handleTracker.Unlock()
+
+	handleTracker.live[h] = struct{}{}
+
+	if !handleTracker.enabled {
+		return
+	}
+
+	handleTracker.leaks[h] = Leak{
+		Resource: resource,
+		Name:     name,
+		Stack:    string(debug.Stack()),
+	}
+}
+
+// untrackHandle removes handle h from the recorded handles
+func untrackHandle(h interface{}) {
+	handleTracker.Lock()
+	defer handleTracker.Unlock()
+
+	delete(handleTracker.live, h)
+	delete(handleTracker.leaks, h)
+}
+
+// liveHandles returns all the devices, graphs and FIFOs which have not been destroyed yet
+func liveHandles() ([]*Device, []*Graph, []*Fifo) {
+	handleTracker.Lock()
+	defer handleTracker.Unlock()
+
+	var devices []*Device
+	var graphs []*Graph
+	var fifos []*Fifo
+
+	for h := range handleTracker.live {
+		switch h := h.(type) {
+		case *Device:
+			devices = append(devices, h)
+		case *Graph:
+			graphs = append(graphs, h)
+		case *Fifo:
+			fifos = append(fifos, h)
+		}
+	}
+
+	return devices, graphs, fifos
+}
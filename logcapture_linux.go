@@ -0,0 +1,69 @@
+package ncs
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// CaptureSDKLog redirects the process standard error, which NCSDK writes its diagnostic messages to,
+// into l line by line. Use SetLogLevel to control the NCSDK verbosity.
+// It returns a function which restores the original standard error and waits until all the captured
+// messages have been logged.
+//
+// The redirection applies to the whole process: everything written to the standard error,
+// including the messages written by Go runtime, is sent to l until the capture is stopped.
+// Hence l must not write to the standard error itself. Only one capture should be active at a time.
+func CaptureSDKLog(l Logger) (func() error, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := syscall.Dup(syscall.Stderr)
+	if err != nil {
+		r.Close()
+		w.Close()
+		return nil, err
+	}
+
+	if err := syscall.Dup3(int(w.Fd()), syscall.Stderr, 0); err != nil {
+		syscall.Close(saved)
+		r.Close()
+		w.Close()
+		return nil, err
+	}
+	// standard error now holds the only reference to the write end of the pipe
+	w.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// read until the write end is closed, so the writers never block on a full pipe
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadString('\n')
+			if line != "" {
+				logf(l, "ncsdk: %s", strings.TrimRight(line, "\n"))
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	stop := func() error {
+		err := syscall.Dup3(saved, syscall.Stderr, 0)
+		syscall.Close(saved)
+		if err != nil {
+			return err
+		}
+
+		<-done
+
+		return r.Close()
+	}
+
+	return stop, nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package ncs
+
+import "fmt"
+
+// CaptureSDKLog redirects the NCSDK diagnostic messages into l.
+// It is only supported on Linux, on other platforms it returns error wrapping ErrUnsupportedFeature.
+func CaptureSDKLog(l Logger) (func() error, error) {
+	return nil, fmt.Errorf("SDK log capture not supported on this platform: %w", ErrUnsupportedFeature)
+}
@@ -0,0 +1,100 @@
+package ncs
+
+// #cgo LDFLAGS: -lmvnc
+/*
+#include <ncs.h>
+*/
+import "C"
+import (
+	"sync"
+)
+
+// metaToken is the Go value referenced by a token along with the handles the token was passed to
+type metaToken struct {
+	value interface{}
+	// device is the device the FIFO element carrying the token was written to
+	device *Device
+	// fifos are the FIFOs the FIFO element carrying the token was written to
+	fifos []*Fifo
+}
+
+// metaTokens maps opaque tokens passed to NCSDK as FIFO element user parameters to Go values.
+// Go pointers must not be retained by C code, so rather than passing the Go values to NCSDK
+// we pass tokens which carry the ids of the values stored in this map. The tokens are plain integers,
+// so the values of the elements which are never read can be released without leaving NCSDK
+// with dangling pointers.
+var metaTokens = struct {
+	sync.Mutex
+	next   uint64
+	values map[uint64]metaToken
+}{
+	values: make(map[uint64]metaToken),
+}
+
+// newMetaToken stores v and returns token which references it. The token is tracked along with
+// the device and fifos the FIFO element carrying it is written to, see releaseFifoMetaTokens.
+// It returns 0 if v is nil. The returned token must be released by releaseMetaToken.
+func newMetaToken(v interface{}, device *Device, fifos ...*Fifo) C.uintptr_t {
+	if v == nil {
+		return 0
+	}
+
+	metaTokens.Lock()
+	defer metaTokens.Unlock()
+
+	metaTokens.next++
+	id := metaTokens.next
+	metaTokens.values[id] = metaToken{value: v, device: device, fifos: fifos}
+
+	return C.uintptr_t(id)
+}
+
+// releaseMetaToken releases the token and returns the Go value it references.
+// It returns nil if token is 0 or if it has already been released.
+func releaseMetaToken(token C.uintptr_t) interface{} {
+	if token == 0 {
+		return nil
+	}
+
+	metaTokens.Lock()
+	defer metaTokens.Unlock()
+
+	id := uint64(token)
+	v := metaTokens.values[id]
+	delete(metaTokens.values, id)
+
+	return v.value
+}
+
+// releaseFifoMetaTokens releases the tokens of the elements written to f which have not been read.
+// The elements are lost once f is destroyed, so nothing is left to release their tokens.
+func releaseFifoMetaTokens(f *Fifo) {
+	releaseMetaTokens(func(t metaToken) bool {
+		for _, ff := range t.fifos {
+			if ff == f {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// releaseDeviceMetaTokens releases the tokens of the elements written to the FIFOs allocated on d
+// which have not been read. The elements are lost once d is closed.
+func releaseDeviceMetaTokens(d *Device) {
+	releaseMetaTokens(func(t metaToken) bool {
+		return t.device == d
+	})
+}
+
+// releaseMetaTokens releases the tokens matched by match
+func releaseMetaTokens(match func(metaToken) bool) {
+	metaTokens.Lock()
+	defer metaTokens.Unlock()
+
+	for id, t := range metaTokens.values {
+		if match(t) {
+			delete(metaTokens.values, id)
+		}
+	}
+}
@@ -1,43 +1,93 @@
 #include "ncs.h"
 #include <stdio.h>
 
+int ncs_GlobalGetOption(int option, void *data, unsigned int *dataLength) {
+    if (data == NULL || dataLength == NULL) {
+        return int(NC_INVALID_PARAMETERS);
+    }
+
+    ncStatus_t s = ncGlobalGetOption(option, data, dataLength);
+    return int(s);
+}
+
+int ncs_GlobalSetOption(int option, const void *data, unsigned int dataLength) {
+    if (data == NULL) {
+        return int(NC_INVALID_PARAMETERS);
+    }
+
+    ncStatus_t s = ncGlobalSetOption(option, data, dataLength);
+    return int(s);
+}
+
 int ncs_DeviceCreate(int idx, void** deviceHandle) {
+    if (deviceHandle == NULL) {
+        return int(NC_INVALID_HANDLE);
+    }
+
     ncStatus_t s = ncDeviceCreate(idx, (struct ncDeviceHandle_t**) deviceHandle);
     return int(s);
 }
 
 int ncs_DeviceOpen(void* deviceHandle) {
+    if (deviceHandle == NULL) {
+        return int(NC_INVALID_HANDLE);
+    }
+
     ncStatus_t s = ncDeviceOpen((struct ncDeviceHandle_t*) deviceHandle);
     return int(s);
 }
 
 int ncs_DeviceGetOption(void* deviceHandle, int option, void *data, unsigned int *dataLength) {
+        if (deviceHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncDeviceGetOption((struct ncDeviceHandle_t*) deviceHandle, option, data, dataLength);
         return int(s);
 }
 
 int ncs_DeviceClose(void* deviceHandle) {
+    if (deviceHandle == NULL) {
+        return int(NC_INVALID_HANDLE);
+    }
+
     ncStatus_t s = ncDeviceClose((struct ncDeviceHandle_t*) deviceHandle);
     return int(s);
 }
 
 int ncs_DeviceDestroy(void** deviceHandle) {
+    if (deviceHandle == NULL || *deviceHandle == NULL) {
+        return int(NC_INVALID_HANDLE);
+    }
+
     ncStatus_t s = ncDeviceDestroy((struct ncDeviceHandle_t**) deviceHandle);
     return int(s);
 }
 
 int ncs_GraphCreate(const char* name, void** graphHandle) {
+        if (graphHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncGraphCreate(name, (struct ncGraphHandle_t**) graphHandle);
         return int(s);
 }
 
 int ncs_GraphAllocate(void* deviceHandle, void* graphHandle, const void *graphBuffer, unsigned int graphBufferLength) {
+        if (deviceHandle == NULL || graphHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncGraphAllocate((struct ncDeviceHandle_t*) deviceHandle, (struct ncGraphHandle_t*) graphHandle,
                         graphBuffer, graphBufferLength);
         return int(s);
 }
 
 int ncs_GraphAllocateWithFifos(void* deviceHandle, void* graphHandle, const void *graphBuffer, unsigned int graphBufferLength, void** inFifoHandle, void** outFifoHandle) {
+        if (deviceHandle == NULL || graphHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         int s = ncs_GraphAllocateWithFifosEx(deviceHandle,
                         graphHandle, graphBuffer, graphBufferLength,
                         inFifoHandle, NC_FIFO_HOST_WO, 2, NC_FIFO_FP32,
@@ -48,6 +98,10 @@ int ncs_GraphAllocateWithFifos(void* deviceHandle, void* graphHandle, const void
 
 
 int ncs_GraphAllocateWithFifosEx(void* deviceHandle, void* graphHandle, const void *graphBuffer, unsigned int graphBufferLength, void** inFifoHandle, ncFifoType_t inFifoType, int inNumElem, ncFifoDataType_t inDataType, void** outFifoHandle,  ncFifoType_t outFifoType, int outNumElem, ncFifoDataType_t outDataType) {
+        if (deviceHandle == NULL || graphHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncGraphAllocateWithFifosEx((struct ncDeviceHandle_t*) deviceHandle,
                         (struct ncGraphHandle_t*) graphHandle, graphBuffer, graphBufferLength,
                         (struct ncFifoHandle_t**) inFifoHandle, inFifoType, inNumElem, inDataType,
@@ -56,56 +110,107 @@ int ncs_GraphAllocateWithFifosEx(void* deviceHandle, void* graphHandle, const vo
 }
 
 int ncs_GraphQueueInference(void* graphHandle, void** inFifoHandle, unsigned int inFifoCount, void** outFifoHandle, unsigned int outFifoCount) {
+        if (graphHandle == NULL || inFifoHandle == NULL || outFifoHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncGraphQueueInference((struct ncGraphHandle_t*) graphHandle,
                         (struct ncFifoHandle_t**) inFifoHandle, inFifoCount,
                         (struct ncFifoHandle_t**) outFifoHandle, outFifoCount);
         return int(s);
 }
 
-int ncs_GraphQueueInferenceWithFifoElem(void* graphHandle, void* inFifoHandle, void* outFifoHandle, const void* inputTensor, unsigned int* inputTensorLength, void* userParam) {
+int ncs_GraphQueueInferenceWithFifoElem(void* graphHandle, void* inFifoHandle, void* outFifoHandle, const void* inputTensor, unsigned int* inputTensorLength, uintptr_t userParam) {
+        if (graphHandle == NULL || inFifoHandle == NULL || outFifoHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncGraphQueueInferenceWithFifoElem((struct ncGraphHandle_t*) graphHandle,
                         (struct ncFifoHandle_t*) inFifoHandle,
                         (struct ncFifoHandle_t*) outFifoHandle,
-                        inputTensor, inputTensorLength, userParam);
+                        inputTensor, inputTensorLength, (void*) userParam);
         return int(s);
 }
 
 int ncs_GraphGetOption(void* graphHandle, int option, void *data, unsigned int *dataLength) {
+        if (graphHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncGraphGetOption((struct ncGraphHandle_t*) graphHandle, option, data, dataLength);
         return int(s);
 }
 
 int ncs_GraphDestroy(void** graphHandle) {
+        if (graphHandle == NULL || *graphHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncGraphDestroy((struct ncGraphHandle_t**) graphHandle);
         return int(s);
 }
 
 int ncs_FifoCreate(const char* name, ncFifoType_t type, void** fifoHandle) {
+        if (fifoHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncFifoCreate(name, type, (struct ncFifoHandle_t**) fifoHandle);
         return int(s);
 }
 
 int ncs_FifoAllocate(void* fifoHandle, void* deviceHandle, struct ncTensorDescriptor_t* tensorDesc, unsigned int numElem) {
+        if (fifoHandle == NULL || deviceHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncFifoAllocate((struct ncFifoHandle_t*) fifoHandle, (struct ncDeviceHandle_t*) deviceHandle,
                         (struct ncTensorDescriptor_t*) tensorDesc, numElem);
         return int(s);
 }
 int ncs_FifoGetOption(void* fifoHandle, int option, void *data, unsigned int *dataLength) {
+        if (fifoHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncFifoGetOption((struct ncFifoHandle_t*) fifoHandle, option, data, dataLength);
         return int(s);
 }
 
-int ncs_FifoWriteElem(void* fifoHandle, const void *inputTensor, unsigned int* inputTensorLength, void* userParam) {
-        ncStatus_t s = ncFifoWriteElem((struct ncFifoHandle_t*) fifoHandle, inputTensor, inputTensorLength, userParam);
+int ncs_FifoSetOption(void* fifoHandle, int option, const void *data, unsigned int dataLength) {
+        if (fifoHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
+        ncStatus_t s = ncFifoSetOption((struct ncFifoHandle_t*) fifoHandle, option, data, dataLength);
         return int(s);
 }
 
-int ncs_FifoReadElem(void* fifoHandle, void *outputData, unsigned int* outputDataLen, void **userParam) {
-        ncStatus_t s = ncFifoReadElem((struct ncFifoHandle_t*) fifoHandle, outputData, outputDataLen, userParam);
+int ncs_FifoWriteElem(void* fifoHandle, const void *inputTensor, unsigned int* inputTensorLength, uintptr_t userParam) {
+        if (fifoHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
+        ncStatus_t s = ncFifoWriteElem((struct ncFifoHandle_t*) fifoHandle, inputTensor, inputTensorLength, (void*) userParam);
+        return int(s);
+}
+
+int ncs_FifoReadElem(void* fifoHandle, void *outputData, unsigned int* outputDataLen, uintptr_t* userParam) {
+        if (fifoHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
+        void* param = NULL;
+        ncStatus_t s = ncFifoReadElem((struct ncFifoHandle_t*) fifoHandle, outputData, outputDataLen, &param);
+        *userParam = (uintptr_t) param;
         return int(s);
 }
 
 int ncs_FifoDestroy(void** fifoHandle) {
+        if (fifoHandle == NULL || *fifoHandle == NULL) {
+                return int(NC_INVALID_HANDLE);
+        }
+
         ncStatus_t s = ncFifoDestroy((struct ncFifoHandle_t**) fifoHandle);
         return int(s);
 }
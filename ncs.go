@@ -7,4 +7,12 @@
 
 // For more information about how to install the SDK go here:
 // https://movidius.github.io/ncsdk/install.html
+//
+// # Concurrency
+//
+// Device, Graph and Fifo handles are safe for concurrent use by multiple goroutines.
+// Calls which only use a handle, such as querying options, queueing inferences or reading and
+// writing FIFO elements, may run concurrently. Calls which change the state of a handle, such as
+// Open, Allocate, Close or Destroy, wait for all the in-flight calls on the same handle to finish
+// and block any new calls until they return.
 package ncs
@@ -1,6 +1,7 @@
 #ifndef _NCS_H_
 #define _NCS_H_
 
+#include <stdint.h>
 #include <stdlib.h>
 #include <mvnc.h>
 
@@ -11,6 +12,10 @@ extern "C" {
 typedef ncFifoType_t ncFifoType;
 typedef ncFifoDataType_t ncFifoDataType;
 
+// Global Functions
+int ncs_GlobalGetOption(int option, void *data, unsigned int *dataLength);
+int ncs_GlobalSetOption(int option, const void *data, unsigned int dataLength);
+
 // Device Functions
 int ncs_DeviceCreate(int idx, void **deviceHandle);
 int ncs_DeviceOpen(void* deviceHandle);
@@ -33,7 +38,7 @@ int ncs_GraphQueueInference(void* graphHandle,
                 void** inFifoHandle, unsigned int inFifoCount,
                 void** outFifoHandle, unsigned int outFifoCount);
 int ncs_GraphQueueInferenceWithFifoElem(void* graphHandle, void* inFifoHandle, void* outFifoHandle,
-                const void* inputTensor, unsigned int* inputTensorLength, void* userParam);
+                const void* inputTensor, unsigned int* inputTensorLength, uintptr_t userParam);
 int ncs_GraphGetOption(void* graphHandle, int option, void *data, unsigned int *dataLength);
 int ncs_GraphDestroy(void **graphHandle);
 
@@ -42,8 +47,9 @@ int ncs_FifoCreate(const char* name, ncFifoType_t type, void** fifoHandle);
 int ncs_FifoAllocate(void* fifoHandle, void* deviceHandle, struct ncTensorDescriptor_t* tensorDesc, unsigned int numElem);
 
 int ncs_FifoGetOption(void* fifoHandle, int option, void *data, unsigned int *dataLength);
-int ncs_FifoWriteElem(void* fifoHandle, const void* inputTensor, unsigned int* inputTensorLength, void* userParam);
-int ncs_FifoReadElem(void* fifoHandle, void *outputData, unsigned int* outputDataLen, void **userParam);
+int ncs_FifoSetOption(void* fifoHandle, int option, const void *data, unsigned int dataLength);
+int ncs_FifoWriteElem(void* fifoHandle, const void* inputTensor, unsigned int* inputTensorLength, uintptr_t userParam);
+int ncs_FifoReadElem(void* fifoHandle, void *outputData, unsigned int* outputDataLen, uintptr_t* userParam);
 int ncs_FifoDestroy(void** fifoHandle);
 
 #ifdef __cplusplus
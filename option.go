@@ -0,0 +1,36 @@
+package ncs
+
+import "fmt"
+
+// OptionGetter queries the options of a device, graph or FIFO and returns them encoded in a byte slice.
+// It is implemented by Device, Graph and Fifo.
+type OptionGetter[O Option] interface {
+	GetOption(opt O) ([]byte, error)
+}
+
+// GetOption queries option opt via h and decodes it into its native type T.
+// It returns error if it fails to query or decode the option or if T is not the option native type.
+//
+// For example, the memory used on the device can be queried as follows:
+//
+//	used, err := ncs.GetOption[uint](dev, ncs.RODeviceMemoryUsed)
+func GetOption[T any, O Option](h OptionGetter[O], opt O) (T, error) {
+	var res T
+
+	data, err := h.GetOption(opt)
+	if err != nil {
+		return res, err
+	}
+
+	val, err := opt.Decode(data)
+	if err != nil {
+		return res, err
+	}
+
+	res, ok := val.(T)
+	if !ok {
+		return res, fmt.Errorf("Invalid type for option %v: option decodes into %T, not %T: %w", opt, val, res, ErrInvalidParameters)
+	}
+
+	return res, nil
+}
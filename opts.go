@@ -0,0 +1,172 @@
+package ncs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Logger logs the lifecycle events of device, graph and FIFO handles.
+// It is satisfied by *log.Logger from the standard library.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// optKind identifies handle options, so that the constructors can reject the options they do not support
+type optKind uint
+
+const (
+	optTimeout optKind = 1 << iota
+	optRetry
+	optLogger
+	optAllocator
+	optIndex
+	optSerial
+)
+
+// optNames maps handle options to the names of the functions which set them
+var optNames = []struct {
+	kind optKind
+	name string
+}{
+	{optTimeout, "WithTimeout"},
+	{optRetry, "WithRetry"},
+	{optLogger, "WithLogger"},
+	{optAllocator, "WithAllocator"},
+	{optIndex, "WithIndex"},
+	{optSerial, "WithSerial"},
+}
+
+// String implements fmt.Stringer interface
+func (k optKind) String() string {
+	var names []string
+	for _, opt := range optNames {
+		if k&opt.kind != 0 {
+			names = append(names, opt.name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// resourceOpts maps resources to the options their handles support
+var resourceOpts = map[string]optKind{
+	"device": optTimeout | optRetry | optLogger | optIndex | optSerial,
+	"graph":  optTimeout | optRetry | optLogger | optAllocator,
+	"fifo":   optTimeout | optRetry | optLogger | optAllocator,
+}
+
+// handleOpts stores the settings which can be attached to handles at construction
+type handleOpts struct {
+	// set records the options which have been set
+	set     optKind
+	timeout time.Duration
+	retry   *RetryPolicy
+	logger  Logger
+	alloc   Allocator
+	index   int
+	serial  string
+}
+
+// Opt configures device, graph or FIFO handle at construction.
+// Constructors return error wrapping ErrInvalidParameters if they are passed an option their handle does not support.
+type Opt func(*handleOpts)
+
+// WithTimeout sets the timeout of the potentially blocking handle calls.
+// It has the same effect as calling SetTimeout on the created handle.
+func WithTimeout(t time.Duration) Opt {
+	return func(o *handleOpts) {
+		o.set |= optTimeout
+		o.timeout = t
+	}
+}
+
+// WithRetry sets the policy used to retry the handle calls which fail with StatusBusy or StatusTimeout.
+// It has the same effect as calling SetRetryPolicy on the created handle.
+func WithRetry(p *RetryPolicy) Opt {
+	return func(o *handleOpts) {
+		o.set |= optRetry
+		o.retry = p
+	}
+}
+
+// WithLogger sets the logger which logs the lifecycle events of the handle such as
+// opening, allocating, closing or destroying it. FIFOs allocated along with a graph inherit the graph logger.
+func WithLogger(l Logger) Opt {
+	return func(o *handleOpts) {
+		o.set |= optLogger
+		o.logger = l
+	}
+}
+
+// WithAllocator sets the allocator of the buffers which hold the data of the elements read from FIFOs.
+// It has the same effect as calling SetAllocator on the created FIFO. FIFOs allocated along with a graph
+// inherit the graph allocator. It is only supported by graphs and FIFOs.
+func WithAllocator(a Allocator) Opt {
+	return func(o *handleOpts) {
+		o.set |= optAllocator
+		o.alloc = a
+	}
+}
+
+// WithIndex selects the device with the given index, overriding the index passed to the constructor.
+// This allows the device to be selected by the code which configures the options, e.g. when the options
+// are passed to NewDeviceFromEnv or to a session. It is only supported by devices.
+func WithIndex(index int) Opt {
+	return func(o *handleOpts) {
+		o.set |= optIndex
+		o.index = index
+	}
+}
+
+// WithSerial selects the device with the given USB serial number, overriding the index passed to the constructor.
+// The device is looked up among USBDevices and then matched against Devices by its USB port path,
+// so it is only supported on the platforms which support USBDevices. The constructor returns error wrapping
+// ErrDeviceNotFound if there is no such device. It is only supported by devices and can't be used along with WithIndex.
+func WithSerial(serial string) Opt {
+	return func(o *handleOpts) {
+		o.set |= optSerial
+		o.serial = serial
+	}
+}
+
+// newHandleOpts applies opts to the default settings of resource handle and returns them.
+// It returns error if any of the opts is not supported by the resource handle.
+func newHandleOpts(resource string, opts ...Opt) (handleOpts, error) {
+	var o handleOpts
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	if unsupported := o.set &^ resourceOpts[resource]; unsupported != 0 {
+		return o, fmt.Errorf("Option %s not supported by %s: %w", unsupported, resource, ErrInvalidParameters)
+	}
+
+	if o.set&optIndex != 0 && o.set&optSerial != 0 {
+		return o, fmt.Errorf("Options %s are mutually exclusive: %w", optIndex|optSerial, ErrInvalidParameters)
+	}
+
+	return o, nil
+}
+
+// supportedOpts returns the opts which are supported by resource handle
+func supportedOpts(resource string, opts []Opt) []Opt {
+	var supported []Opt
+	for _, opt := range opts {
+		var o handleOpts
+		opt(&o)
+
+		if o.set&^resourceOpts[resource] == 0 {
+			supported = append(supported, opt)
+		}
+	}
+
+	return supported
+}
+
+// logf logs the message via l if l is not nil
+func logf(l Logger, format string, v ...interface{}) {
+	if l != nil {
+		l.Printf(format, v...)
+	}
+}
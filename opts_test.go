@@ -0,0 +1,54 @@
+package ncs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewHandleOpts(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		opts     []Opt
+		err      error
+	}{
+		{name: "DeviceCommon", resource: "device", opts: []Opt{WithTimeout(time.Second), WithRetry(nil), WithLogger(nil)}},
+		{name: "DeviceIndex", resource: "device", opts: []Opt{WithIndex(1)}},
+		{name: "DeviceSerial", resource: "device", opts: []Opt{WithSerial("0123")}},
+		{name: "DeviceAllocator", resource: "device", opts: []Opt{WithAllocator(nil)}, err: ErrInvalidParameters},
+		{name: "DeviceIndexSerial", resource: "device", opts: []Opt{WithIndex(1), WithSerial("0123")}, err: ErrInvalidParameters},
+		{name: "GraphAllocator", resource: "graph", opts: []Opt{WithTimeout(time.Second), WithAllocator(nil)}},
+		{name: "GraphIndex", resource: "graph", opts: []Opt{WithIndex(1)}, err: ErrInvalidParameters},
+		{name: "FifoAllocator", resource: "fifo", opts: []Opt{WithLogger(nil), WithAllocator(nil)}},
+		{name: "FifoSerial", resource: "fifo", opts: []Opt{WithSerial("0123")}, err: ErrInvalidParameters},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newHandleOpts(tc.resource, tc.opts...)
+			if tc.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error %v, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestSupportedOpts(t *testing.T) {
+	opts := []Opt{WithTimeout(time.Second), WithAllocator(nil), WithIndex(1)}
+
+	if n := len(supportedOpts("device", opts)); n != 2 {
+		t.Errorf("expected 2 device options, got: %d", n)
+	}
+
+	if n := len(supportedOpts("graph", opts)); n != 2 {
+		t.Errorf("expected 2 graph options, got: %d", n)
+	}
+}
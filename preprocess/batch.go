@@ -0,0 +1,112 @@
+package preprocess
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// Transform converts frame src and stores the result in dst, which is reused if it has enough capacity.
+// It returns the converted frame. The frame conversions in this package, such as BGRToRGB or RGBToGray, are Transforms.
+// Transform must not modify src.
+type Transform func(dst, src []byte) ([]byte, error)
+
+// Normalize returns Transform which converts 8 bit samples to little-endian FP32 values (v - mean) * scale,
+// which is the input format of FP32 FIFOs. For example Normalize(127.5, 1/127.5) scales the samples into [-1,1].
+func Normalize(mean, scale float32) Transform {
+	return func(dst, src []byte) ([]byte, error) {
+		dst = buffer(dst, 4*len(src))
+		for i, v := range src {
+			binary.LittleEndian.PutUint32(dst[4*i:], math.Float32bits((float32(v)-mean)*scale))
+		}
+
+		return dst, nil
+	}
+}
+
+// chain applies transforms to frames using its own scratch buffers
+type chain struct {
+	transforms []Transform
+	scratch    [2][]byte
+}
+
+// apply applies the chain transforms to src in order and stores the result in dst
+func (c *chain) apply(dst, src []byte) ([]byte, error) {
+	if len(c.transforms) == 0 {
+		dst = buffer(dst, len(src))
+		copy(dst, src)
+		return dst, nil
+	}
+
+	var err error
+	in := src
+
+	// the intermediate results alternate between the scratch buffers, so the transforms never write into their input
+	for i, t := range c.transforms {
+		if i == len(c.transforms)-1 {
+			return t(dst, in)
+		}
+
+		s := i % 2
+		if c.scratch[s], err = t(c.scratch[s], in); err != nil {
+			return nil, err
+		}
+		in = c.scratch[s]
+	}
+
+	return dst, nil
+}
+
+// Batch applies transforms in order to all frames and returns the transformed frames.
+// The frames are processed in parallel by workers goroutines; if workers is not positive, GOMAXPROCS workers are used.
+// Every worker keeps its own scratch buffers for the intermediate results, so processing the batch does not
+// allocate per frame. The transformed frames are stored in dst, whose buffers are reused if they have enough capacity,
+// so the same dst can be passed to Batch for every batch of frames.
+// Batch does not run any inferences: NCSDK graphs take one input tensor per inference, so the transformed frames
+// are queued one by one, e.g. via ncs.Infer or Graph.QueueInferenceWithFifoElem, in the same order as frames.
+// It returns error if transforming any of the frames fails.
+func Batch(dst, frames [][]byte, workers int, transforms ...Transform) ([][]byte, error) {
+	if len(dst) < len(frames) {
+		dst = append(dst, make([][]byte, len(frames)-len(dst))...)
+	}
+	dst = dst[:len(frames)]
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(frames) {
+		workers = len(frames)
+	}
+
+	idx := make(chan int)
+	errs := make([]error, len(frames))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			c := &chain{transforms: transforms}
+			for i := range idx {
+				dst[i], errs[i] = c.apply(dst[i], frames[i])
+			}
+		}()
+	}
+
+	for i := range frames {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("Frame %d: %w", i, err)
+		}
+	}
+
+	return dst, nil
+}
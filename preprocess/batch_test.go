@@ -0,0 +1,130 @@
+package preprocess
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// counter counts the buffers allocated by the transforms
+type counter struct {
+	allocs atomic.Int32
+}
+
+// inc returns Transform which adds 1 to every sample. It fails the test if dst shares its buffer with src.
+func (c *counter) inc(t *testing.T) Transform {
+	return func(dst, src []byte) ([]byte, error) {
+		if cap(dst) > 0 && len(src) > 0 && &dst[:1][0] == &src[0] {
+			t.Errorf("transform called with dst aliasing src")
+		}
+
+		if cap(dst) < len(src) {
+			c.allocs.Add(1)
+		}
+
+		dst = buffer(dst, len(src))
+		for i, v := range src {
+			dst[i] = v + 1
+		}
+
+		return dst, nil
+	}
+}
+
+func TestBatch(t *testing.T) {
+	frames := [][]byte{{0, 1, 2}, {10, 11, 12}, {20, 21, 22}, {30, 31, 32}}
+	want := [][]byte{{3, 4, 5}, {13, 14, 15}, {23, 24, 25}, {33, 34, 35}}
+
+	for _, workers := range []int{0, 1, 2, 8} {
+		var c counter
+
+		dst, err := Batch(nil, frames, workers, c.inc(t), c.inc(t), c.inc(t))
+		if err != nil {
+			t.Fatalf("workers %d: failed to process batch: %v", workers, err)
+		}
+
+		for i := range want {
+			if !bytes.Equal(dst[i], want[i]) {
+				t.Errorf("workers %d: frame %d: expected %v, got: %v", workers, i, want[i], dst[i])
+			}
+		}
+	}
+
+	if !bytes.Equal(frames[0], []byte{0, 1, 2}) {
+		t.Errorf("expected frames not to be modified, got: %v", frames[0])
+	}
+}
+
+func TestBatchBuffers(t *testing.T) {
+	frames := [][]byte{{0, 1, 2}, {10, 11, 12}, {20, 21, 22}}
+
+	var c counter
+
+	// a single worker allocates both scratch buffers once and a dst buffer per frame
+	dst, err := Batch(nil, frames, 1, c.inc(t), c.inc(t), c.inc(t))
+	if err != nil {
+		t.Fatalf("failed to process batch: %v", err)
+	}
+
+	if n := c.allocs.Load(); n != 2+int32(len(frames)) {
+		t.Errorf("expected %d allocations, got: %d", 2+len(frames), n)
+	}
+
+	first := &dst[0][0]
+	c.allocs.Store(0)
+
+	// reusing dst only allocates the scratch buffers of the new batch
+	if dst, err = Batch(dst, frames, 1, c.inc(t), c.inc(t), c.inc(t)); err != nil {
+		t.Fatalf("failed to process batch: %v", err)
+	}
+
+	if n := c.allocs.Load(); n != 2 {
+		t.Errorf("expected 2 allocations, got: %d", n)
+	}
+
+	if &dst[0][0] != first {
+		t.Errorf("expected dst buffers to be reused")
+	}
+
+	// dst is resized to the number of frames
+	if dst, err = Batch(dst, frames[:1], 1); err != nil || len(dst) != 1 {
+		t.Fatalf("expected 1 frame, got: %d, %v", len(dst), err)
+	}
+
+	if !bytes.Equal(dst[0], frames[0]) {
+		t.Errorf("expected frame to be copied without transforms, got: %v", dst[0])
+	}
+}
+
+func TestBatchError(t *testing.T) {
+	frames := [][]byte{{0, 1, 2}, {0, 1}, {0, 1, 2}}
+
+	_, err := Batch(nil, frames, 2, BGRToRGB)
+	if err == nil {
+		t.Fatalf("expected error processing invalid frame")
+	}
+
+	if !strings.HasPrefix(err.Error(), "Frame 1:") {
+		t.Errorf("expected error of frame 1, got: %v", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	got, err := Normalize(127.5, 1/127.5)(nil, []byte{0, 255})
+	if err != nil {
+		t.Fatalf("failed to normalize frame: %v", err)
+	}
+
+	if len(got) != 8 {
+		t.Fatalf("expected 8 bytes, got: %d", len(got))
+	}
+
+	for i, want := range []float32{-1, 1} {
+		if v := math.Float32frombits(binary.LittleEndian.Uint32(got[4*i:])); v != want {
+			t.Errorf("sample %d: expected %v, got: %v", i, want, v)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package preprocess
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// The conversions in this file operate on raw frame buffers of 8 bit samples in row-major order,
+// as produced by cameras and video decoders. The destination buffer dst is reused if it has
+// enough capacity, otherwise a new buffer is allocated. The converted buffer is returned.
+
+// buffer returns dst resliced to size bytes if it has enough capacity, otherwise it returns new buffer
+func buffer(dst []byte, size int) []byte {
+	if cap(dst) >= size {
+		return dst[:size]
+	}
+
+	return make([]byte, size)
+}
+
+// SwapRB swaps the red and blue channels of interleaved 3 channel frame buf in place,
+// converting BGR frame to RGB frame and vice versa. Caffe models usually expect BGR input,
+// whereas TensorFlow models and most image decoders use RGB.
+// It returns error if the length of buf is not a multiple of 3.
+func SwapRB(buf []byte) error {
+	if len(buf)%3 != 0 {
+		return fmt.Errorf("Invalid frame length: %d bytes is not a multiple of 3", len(buf))
+	}
+
+	for i := 0; i < len(buf); i += 3 {
+		buf[i], buf[i+2] = buf[i+2], buf[i]
+	}
+
+	return nil
+}
+
+// BGRToRGB converts interleaved BGR frame src to RGB frame
+func BGRToRGB(dst, src []byte) ([]byte, error) {
+	if len(src)%3 != 0 {
+		return nil, fmt.Errorf("Invalid frame length: %d bytes is not a multiple of 3", len(src))
+	}
+
+	dst = buffer(dst, len(src))
+	copy(dst, src)
+
+	return dst, SwapRB(dst)
+}
+
+// RGBToBGR converts interleaved RGB frame src to BGR frame
+func RGBToBGR(dst, src []byte) ([]byte, error) {
+	return BGRToRGB(dst, src)
+}
+
+// RGBToGray converts interleaved RGB frame src to grayscale frame with one byte per pixel
+// using the ITU-R BT.601 luma coefficients
+func RGBToGray(dst, src []byte) ([]byte, error) {
+	return toGray(dst, src, 0, 2)
+}
+
+// BGRToGray converts interleaved BGR frame src to grayscale frame with one byte per pixel
+// using the ITU-R BT.601 luma coefficients
+func BGRToGray(dst, src []byte) ([]byte, error) {
+	return toGray(dst, src, 2, 0)
+}
+
+// toGray converts interleaved 3 channel frame src with red channel at offset r and blue channel at offset b to grayscale
+func toGray(dst, src []byte, r, b int) ([]byte, error) {
+	if len(src)%3 != 0 {
+		return nil, fmt.Errorf("Invalid frame length: %d bytes is not a multiple of 3", len(src))
+	}
+
+	dst = buffer(dst, len(src)/3)
+	for i := range dst {
+		p := src[3*i : 3*i+3]
+		// 16.16 fixed point BT.601 coefficients, the same as used by image/color
+		y := (19595*uint32(p[r]) + 38470*uint32(p[1]) + 7471*uint32(p[b]) + 1<<15) >> 16
+		dst[i] = uint8(y)
+	}
+
+	return dst, nil
+}
+
+// YUYVToRGB converts packed YUV 4:2:2 frame src of the given width and height, in which every two pixels
+// are stored as Y0 U Y1 V, to interleaved RGB frame. This is the format most USB cameras produce.
+// It returns error if the frame size does not match its dimensions or if width is odd.
+func YUYVToRGB(dst, src []byte, width, height int) ([]byte, error) {
+	if width%2 != 0 || len(src) != 2*width*height {
+		return nil, fmt.Errorf("Invalid YUYV frame: %d bytes for %dx%d frame", len(src), width, height)
+	}
+
+	dst = buffer(dst, 3*width*height)
+	for i, j := 0, 0; i < len(src); i, j = i+4, j+6 {
+		u, v := src[i+1], src[i+3]
+		dst[j], dst[j+1], dst[j+2] = color.YCbCrToRGB(src[i], u, v)
+		dst[j+3], dst[j+4], dst[j+5] = color.YCbCrToRGB(src[i+2], u, v)
+	}
+
+	return dst, nil
+}
+
+// I420ToRGB converts planar YUV 4:2:0 frame src of the given width and height, which contains the full
+// resolution Y plane followed by the quarter resolution U and V planes, to interleaved RGB frame.
+// This is the format most video decoders produce.
+// It returns error if the frame size does not match its dimensions or if any of the dimensions is odd.
+func I420ToRGB(dst, src []byte, width, height int) ([]byte, error) {
+	if width%2 != 0 || height%2 != 0 || len(src) != width*height*3/2 {
+		return nil, fmt.Errorf("Invalid I420 frame: %d bytes for %dx%d frame", len(src), width, height)
+	}
+
+	ySize, cSize := width*height, width*height/4
+	yPlane, uPlane, vPlane := src[:ySize], src[ySize:ySize+cSize], src[ySize+cSize:]
+
+	dst = buffer(dst, 3*width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := (y/2)*(width/2) + x/2
+			j := 3 * (y*width + x)
+			dst[j], dst[j+1], dst[j+2] = color.YCbCrToRGB(yPlane[y*width+x], uPlane[c], vPlane[c])
+		}
+	}
+
+	return dst, nil
+}
@@ -0,0 +1,186 @@
+package preprocess
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+func TestSwapRB(t *testing.T) {
+	buf := []byte{1, 2, 3, 4, 5, 6}
+	if err := SwapRB(buf); err != nil {
+		t.Fatalf("failed to swap channels: %v", err)
+	}
+
+	if want := []byte{3, 2, 1, 6, 5, 4}; !bytes.Equal(buf, want) {
+		t.Errorf("expected %v, got: %v", want, buf)
+	}
+
+	if err := SwapRB(make([]byte, 4)); err == nil {
+		t.Errorf("expected error swapping channels of invalid frame")
+	}
+}
+
+func TestBGRToRGB(t *testing.T) {
+	src := []byte{1, 2, 3, 4, 5, 6}
+	want := []byte{3, 2, 1, 6, 5, 4}
+
+	tests := []struct {
+		name string
+		dst  []byte
+	}{
+		{name: "Nil", dst: nil},
+		{name: "Short", dst: make([]byte, 3)},
+		{name: "Reuse", dst: make([]byte, 0, 8)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := BGRToRGB(tc.dst, src)
+			if err != nil {
+				t.Fatalf("failed to convert frame: %v", err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("expected %v, got: %v", want, got)
+			}
+
+			if cap(tc.dst) >= len(src) && &got[0] != &tc.dst[:1][0] {
+				t.Errorf("expected dst buffer to be reused")
+			}
+		})
+	}
+
+	if !bytes.Equal(src, []byte{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("expected src not to be modified, got: %v", src)
+	}
+
+	if got, err := RGBToBGR(nil, want); err != nil || !bytes.Equal(got, src) {
+		t.Errorf("expected %v, got: %v, %v", src, got, err)
+	}
+
+	if _, err := BGRToRGB(nil, make([]byte, 5)); err == nil {
+		t.Errorf("expected error converting invalid frame")
+	}
+}
+
+func TestToGray(t *testing.T) {
+	rgb := []byte{
+		0, 0, 0,
+		255, 255, 255,
+		255, 0, 0,
+		0, 0, 255,
+		10, 200, 30,
+	}
+
+	want := make([]byte, len(rgb)/3)
+	for i := range want {
+		want[i] = color.GrayModel.Convert(color.RGBA{R: rgb[3*i], G: rgb[3*i+1], B: rgb[3*i+2], A: 255}).(color.Gray).Y
+	}
+
+	got, err := RGBToGray(nil, rgb)
+	if err != nil {
+		t.Fatalf("failed to convert frame: %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("expected %v, got: %v", want, got)
+	}
+
+	bgr, err := RGBToBGR(nil, rgb)
+	if err != nil {
+		t.Fatalf("failed to convert frame: %v", err)
+	}
+
+	if got, err := BGRToGray(nil, bgr); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("expected %v, got: %v, %v", want, got, err)
+	}
+
+	if _, err := RGBToGray(nil, make([]byte, 4)); err == nil {
+		t.Errorf("expected error converting invalid frame")
+	}
+}
+
+// yuvPixel returns the RGB value of YCbCr pixel
+func yuvPixel(y, u, v byte) []byte {
+	r, g, b := color.YCbCrToRGB(y, u, v)
+	return []byte{r, g, b}
+}
+
+func TestYUYVToRGB(t *testing.T) {
+	// 2x2 frame: every row is Y0 U Y1 V
+	src := []byte{
+		16, 90, 235, 240,
+		128, 128, 81, 128,
+	}
+
+	want := bytes.Join([][]byte{
+		yuvPixel(16, 90, 240), yuvPixel(235, 90, 240),
+		yuvPixel(128, 128, 128), yuvPixel(81, 128, 128),
+	}, nil)
+
+	got, err := YUYVToRGB(nil, src, 2, 2)
+	if err != nil {
+		t.Fatalf("failed to convert frame: %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("expected %v, got: %v", want, got)
+	}
+
+	if gray := got[6:9]; !bytes.Equal(gray, []byte{128, 128, 128}) {
+		t.Errorf("expected neutral chroma to produce gray, got: %v", gray)
+	}
+
+	for _, tc := range []struct {
+		name          string
+		src           []byte
+		width, height int
+	}{
+		{name: "OddWidth", src: make([]byte, 6), width: 3, height: 1},
+		{name: "Short", src: make([]byte, 7), width: 2, height: 2},
+		{name: "Long", src: make([]byte, 9), width: 2, height: 2},
+	} {
+		if _, err := YUYVToRGB(nil, tc.src, tc.width, tc.height); err == nil {
+			t.Errorf("%s: expected error converting invalid frame", tc.name)
+		}
+	}
+}
+
+func TestI420ToRGB(t *testing.T) {
+	// 4x2 frame: 8 luma samples followed by 2 U and 2 V samples, each shared by a 2x2 block
+	src := []byte{
+		10, 20, 30, 40,
+		50, 60, 70, 80,
+		90, 160,
+		200, 60,
+	}
+
+	want := bytes.Join([][]byte{
+		yuvPixel(10, 90, 200), yuvPixel(20, 90, 200), yuvPixel(30, 160, 60), yuvPixel(40, 160, 60),
+		yuvPixel(50, 90, 200), yuvPixel(60, 90, 200), yuvPixel(70, 160, 60), yuvPixel(80, 160, 60),
+	}, nil)
+
+	got, err := I420ToRGB(nil, src, 4, 2)
+	if err != nil {
+		t.Fatalf("failed to convert frame: %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("expected %v, got: %v", want, got)
+	}
+
+	for _, tc := range []struct {
+		name          string
+		src           []byte
+		width, height int
+	}{
+		{name: "OddWidth", src: make([]byte, 9), width: 3, height: 2},
+		{name: "OddHeight", src: make([]byte, 9), width: 2, height: 3},
+		{name: "Short", src: make([]byte, 11), width: 4, height: 2},
+	} {
+		if _, err := I420ToRGB(nil, tc.src, tc.width, tc.height); err == nil {
+			t.Errorf("%s: expected error converting invalid frame", tc.name)
+		}
+	}
+}
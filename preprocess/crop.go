@@ -0,0 +1,96 @@
+package preprocess
+
+import (
+	"image"
+	"math"
+
+	"github.com/milosgajdos/ncs/results"
+	xdraw "golang.org/x/image/draw"
+)
+
+// CropOpts configures the regions of interest cropped from images
+type CropOpts struct {
+	// Padding enlarges the region on every side by the given fraction of its width and height,
+	// e.g. 0.1 adds 10% of the region width to its left and right side. Second stage models, such as
+	// face classifiers, often expect some context around the detected object.
+	Padding float64
+	// Square extends the shorter side of the region so the region is square, which avoids distorting
+	// the object when it is resized to a square model input.
+	Square bool
+}
+
+// ROI returns the region of interest of the image with the given bounds described by box b,
+// which is normalized to the image size as returned by detection models.
+// The region is enlarged according to opts and clamped to the image bounds. If opts is nil the region matches the box.
+// Square regions which do not fit the image are shifted inside the bounds, and if the image is narrower
+// than the region they are shrunk to the largest square which fits. It returns empty region if the box
+// does not overlap the image.
+func ROI(bounds image.Rectangle, b results.Box, opts *CropOpts) image.Rectangle {
+	var o CropOpts
+	if opts != nil {
+		o = *opts
+	}
+
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+
+	x0, y0 := float64(bounds.Min.X)+float64(b.XMin)*w, float64(bounds.Min.Y)+float64(b.YMin)*h
+	x1, y1 := float64(bounds.Min.X)+float64(b.XMax)*w, float64(bounds.Min.Y)+float64(b.YMax)*h
+
+	if o.Padding > 0 {
+		px, py := (x1-x0)*o.Padding, (y1-y0)*o.Padding
+		x0, y0, x1, y1 = x0-px, y0-py, x1+px, y1+py
+	}
+
+	r := image.Rect(int(math.Round(x0)), int(math.Round(y0)), int(math.Round(x1)), int(math.Round(y1))).Intersect(bounds)
+	if r.Empty() || !o.Square {
+		return r
+	}
+
+	// square the region around its center before it was clamped
+	side := min(max(int(math.Round(x1-x0)), int(math.Round(y1-y0))), bounds.Dx(), bounds.Dy())
+	cx, cy := int(math.Round((x0+x1)/2)), int(math.Round((y0+y1)/2))
+	r = image.Rect(cx-side/2, cy-side/2, cx-side/2+side, cy-side/2+side)
+
+	// shift the square inside the bounds
+	if r.Min.X < bounds.Min.X {
+		r = r.Add(image.Pt(bounds.Min.X-r.Min.X, 0))
+	}
+	if r.Max.X > bounds.Max.X {
+		r = r.Add(image.Pt(bounds.Max.X-r.Max.X, 0))
+	}
+	if r.Min.Y < bounds.Min.Y {
+		r = r.Add(image.Pt(0, bounds.Min.Y-r.Min.Y))
+	}
+	if r.Max.Y > bounds.Max.Y {
+		r = r.Add(image.Pt(0, bounds.Max.Y-r.Max.Y))
+	}
+
+	return r
+}
+
+// Crop extracts the region of interest of img described by box b and resizes it to width x height
+// with bilinear interpolation, so it can be fed to a second stage model, e.g. to classify the objects
+// detected by a first stage detection model. See ROI for how the region is computed from the box.
+// It returns nil if the region is empty.
+func Crop(img image.Image, b results.Box, width, height int, opts *CropOpts) *image.RGBA {
+	r := ROI(img.Bounds(), b, opts)
+	if r.Empty() {
+		return nil
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.BiLinear.Scale(dst, dst.Bounds(), img, r, xdraw.Src, nil)
+
+	return dst
+}
+
+// CropDetections crops the regions of interest of all dets from img, see Crop for more details.
+// The returned crops are in the same order as dets; the crops of the detections with empty regions are nil.
+func CropDetections(img image.Image, dets results.Detections, width, height int, opts *CropOpts) []*image.RGBA {
+	crops := make([]*image.RGBA, len(dets))
+	for i, det := range dets {
+		crops[i] = Crop(img, det.Box, width, height, opts)
+	}
+
+	return crops
+}
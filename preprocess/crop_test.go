@@ -0,0 +1,72 @@
+package preprocess
+
+import (
+	"image"
+	"testing"
+
+	"github.com/milosgajdos/ncs/results"
+)
+
+func TestROI(t *testing.T) {
+	bounds := image.Rect(0, 0, 100, 80)
+
+	tests := []struct {
+		name   string
+		bounds image.Rectangle
+		box    results.Box
+		opts   *CropOpts
+		want   image.Rectangle
+	}{
+		{name: "Box", box: results.Box{XMin: 0.1, YMin: 0.25, XMax: 0.5, YMax: 0.75}, want: image.Rect(10, 20, 50, 60)},
+		{name: "Padding", box: results.Box{XMin: 0.1, YMin: 0.25, XMax: 0.5, YMax: 0.75}, opts: &CropOpts{Padding: 0.1}, want: image.Rect(6, 16, 54, 64)},
+		{name: "PaddingClamped", box: results.Box{XMax: 0.5, YMax: 0.5}, opts: &CropOpts{Padding: 0.2}, want: image.Rect(0, 0, 60, 48)},
+		{name: "Square", box: results.Box{XMin: 0.1, YMin: 0.25, XMax: 0.5, YMax: 0.5}, opts: &CropOpts{Square: true}, want: image.Rect(10, 10, 50, 50)},
+		{name: "SquareTopLeft", box: results.Box{XMax: 0.4, YMax: 0.25}, opts: &CropOpts{Square: true}, want: image.Rect(0, 0, 40, 40)},
+		{name: "SquareBottomRight", box: results.Box{XMin: 0.9, YMin: 0.5, XMax: 1, YMax: 1}, opts: &CropOpts{Square: true}, want: image.Rect(60, 40, 100, 80)},
+		{name: "SquarePadding", box: results.Box{XMin: 0.9, YMin: 0.5, XMax: 1, YMax: 1}, opts: &CropOpts{Padding: 0.5, Square: true}, want: image.Rect(20, 0, 100, 80)},
+		{name: "SquareLargerThanImage", box: results.Box{XMax: 1, YMax: 1}, opts: &CropOpts{Square: true}, want: image.Rect(10, 0, 90, 80)},
+		{name: "SquareOffset", bounds: image.Rect(100, 50, 200, 130), box: results.Box{XMax: 0.4, YMax: 0.25}, opts: &CropOpts{Square: true}, want: image.Rect(100, 50, 140, 90)},
+		{name: "Empty", box: results.Box{XMin: 0.5, YMin: 0.5, XMax: 0.5, YMax: 0.5}, opts: &CropOpts{Padding: 0.1, Square: true}, want: image.Rectangle{}},
+		{name: "EmptyWidth", box: results.Box{XMin: 0.5, YMin: 0.2, XMax: 0.5, YMax: 0.6}, opts: &CropOpts{Square: true}, want: image.Rectangle{}},
+		{name: "Outside", box: results.Box{XMin: 1.2, YMin: 0.2, XMax: 1.5, YMax: 0.4}, opts: &CropOpts{Square: true}, want: image.Rectangle{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := bounds
+			if !tc.bounds.Empty() {
+				b = tc.bounds
+			}
+
+			got := ROI(b, tc.box, tc.opts)
+			if got != tc.want {
+				t.Errorf("expected %v, got: %v", tc.want, got)
+			}
+
+			if !got.In(b) {
+				t.Errorf("expected %v inside %v", got, b)
+			}
+
+			if tc.opts != nil && tc.opts.Square && got.Dx() != got.Dy() {
+				t.Errorf("expected square region, got: %v", got)
+			}
+		})
+	}
+}
+
+func TestCrop(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 80))
+
+	crop := Crop(img, results.Box{XMin: 0.1, YMin: 0.1, XMax: 0.6, YMax: 0.9}, 32, 24, nil)
+	if crop == nil {
+		t.Fatalf("expected crop, got nil")
+	}
+
+	if b := crop.Bounds(); b != image.Rect(0, 0, 32, 24) {
+		t.Errorf("expected crop bounds %v, got: %v", image.Rect(0, 0, 32, 24), b)
+	}
+
+	if crop := Crop(img, results.Box{XMin: 1.1, XMax: 1.2, YMax: 1}, 32, 24, nil); crop != nil {
+		t.Errorf("expected nil crop of empty region, got: %v", crop.Bounds())
+	}
+}
@@ -0,0 +1,126 @@
+// Package preprocess provides image transforms which prepare the input images for the models run on Neural Compute Stick.
+// It only depends on the standard library image packages and golang.org/x/image, so the images can be preprocessed without OpenCV.
+package preprocess
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/milosgajdos/ncs/results"
+	xdraw "golang.org/x/image/draw"
+)
+
+// Letterbox is an aspect preserving resize of the source image into the model input with padding.
+// The source image is scaled to fit into the input and centered in it, the rest of the input is padded.
+// Letterboxing is required by YOLO family models, which are trained on letterboxed images.
+//
+// The coordinates of the objects detected in the letterboxed image can be mapped back to the source image
+// with MapPoint, MapRect and MapBox.
+type Letterbox struct {
+	// Src is the bounds of the source image
+	Src image.Rectangle
+	// Width is the width of the letterboxed image
+	Width int
+	// Height is the height of the letterboxed image
+	Height int
+	// Scale is the factor the source image is scaled by
+	Scale float64
+	// Dx is the horizontal padding on the left of the scaled source image
+	Dx int
+	// Dy is the vertical padding on the top of the scaled source image
+	Dy int
+}
+
+// NewLetterbox returns Letterbox which fits the image with bounds src into width x height image
+func NewLetterbox(src image.Rectangle, width, height int) Letterbox {
+	l := Letterbox{Src: src, Width: width, Height: height}
+
+	if src.Dx() == 0 || src.Dy() == 0 {
+		return l
+	}
+
+	l.Scale = math.Min(float64(width)/float64(src.Dx()), float64(height)/float64(src.Dy()))
+	w, h := l.scaled()
+	l.Dx, l.Dy = (width-w)/2, (height-h)/2
+
+	return l
+}
+
+// scaled returns the size of the scaled source image
+func (l Letterbox) scaled() (int, int) {
+	return int(math.Round(float64(l.Src.Dx()) * l.Scale)), int(math.Round(float64(l.Src.Dy()) * l.Scale))
+}
+
+// Bounds returns the bounds of the scaled source image within the letterboxed image
+func (l Letterbox) Bounds() image.Rectangle {
+	w, h := l.scaled()
+
+	return image.Rect(l.Dx, l.Dy, l.Dx+w, l.Dy+h)
+}
+
+// Apply scales img with bilinear interpolation and returns it letterboxed and padded with fill color.
+// img is expected to have the bounds the letterbox has been created for.
+func (l Letterbox) Apply(img image.Image, fill color.Color) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, l.Width, l.Height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(fill), image.Point{}, draw.Src)
+	xdraw.BiLinear.Scale(dst, l.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+
+	return dst
+}
+
+// MapPoint maps point p in the letterboxed image coordinates to the source image coordinates.
+// The mapped point is clamped to the source image bounds.
+func (l Letterbox) MapPoint(p image.Point) image.Point {
+	x, y := l.mapXY(float64(p.X), float64(p.Y))
+
+	return image.Pt(int(math.Round(x)), int(math.Round(y)))
+}
+
+// MapRect maps rectangle r in the letterboxed image coordinates to the source image coordinates.
+// The mapped rectangle is clamped to the source image bounds.
+func (l Letterbox) MapRect(r image.Rectangle) image.Rectangle {
+	return image.Rectangle{Min: l.MapPoint(r.Min), Max: l.MapPoint(r.Max)}
+}
+
+// MapBox maps box b normalized to the letterboxed image size to the box normalized to the source image size.
+// The detection models return boxes normalized to their input size, i.e. to the letterboxed image.
+func (l Letterbox) MapBox(b results.Box) results.Box {
+	if l.Src.Dx() == 0 || l.Src.Dy() == 0 {
+		return results.Box{}
+	}
+
+	norm := func(x, y float64) (float32, float32) {
+		x, y = l.mapXY(x*float64(l.Width), y*float64(l.Height))
+		return float32((x - float64(l.Src.Min.X)) / float64(l.Src.Dx())), float32((y - float64(l.Src.Min.Y)) / float64(l.Src.Dy()))
+	}
+
+	var res results.Box
+	res.XMin, res.YMin = norm(float64(b.XMin), float64(b.YMin))
+	res.XMax, res.YMax = norm(float64(b.XMax), float64(b.YMax))
+
+	return res
+}
+
+// MapDetections maps the boxes of dets detected in the letterboxed image to the source image in place.
+func (l Letterbox) MapDetections(dets results.Detections) {
+	for i := range dets {
+		dets[i].Box = l.MapBox(dets[i].Box)
+	}
+}
+
+// mapXY maps x, y in the letterboxed image coordinates to the source image coordinates clamped to its bounds
+func (l Letterbox) mapXY(x, y float64) (float64, float64) {
+	if l.Scale == 0 {
+		return float64(l.Src.Min.X), float64(l.Src.Min.Y)
+	}
+
+	x = (x-float64(l.Dx))/l.Scale + float64(l.Src.Min.X)
+	y = (y-float64(l.Dy))/l.Scale + float64(l.Src.Min.Y)
+
+	x = math.Max(float64(l.Src.Min.X), math.Min(x, float64(l.Src.Max.X)))
+	y = math.Max(float64(l.Src.Min.Y), math.Min(y, float64(l.Src.Max.Y)))
+
+	return x, y
+}
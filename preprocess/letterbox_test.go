@@ -0,0 +1,181 @@
+package preprocess
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/milosgajdos/ncs/results"
+)
+
+func TestNewLetterbox(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    image.Rectangle
+		scale  float64
+		bounds image.Rectangle
+	}{
+		{name: "Wide", src: image.Rect(0, 0, 640, 480), scale: 0.65, bounds: image.Rect(0, 52, 416, 364)},
+		{name: "Tall", src: image.Rect(0, 0, 480, 640), scale: 0.65, bounds: image.Rect(52, 0, 364, 416)},
+		{name: "Square", src: image.Rect(0, 0, 208, 208), scale: 2, bounds: image.Rect(0, 0, 416, 416)},
+		{name: "Offset", src: image.Rect(100, 50, 740, 530), scale: 0.65, bounds: image.Rect(0, 52, 416, 364)},
+		{name: "Empty", src: image.Rect(0, 0, 0, 480), scale: 0, bounds: image.Rect(0, 0, 0, 0)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l := NewLetterbox(tc.src, 416, 416)
+
+			if math.Abs(l.Scale-tc.scale) > 1e-9 {
+				t.Errorf("expected scale %v, got: %v", tc.scale, l.Scale)
+			}
+
+			if b := l.Bounds(); b != tc.bounds {
+				t.Errorf("expected bounds %v, got: %v", tc.bounds, b)
+			}
+		})
+	}
+}
+
+func TestLetterboxMapPoint(t *testing.T) {
+	tests := []struct {
+		name string
+		src  image.Rectangle
+		p    image.Point
+		want image.Point
+	}{
+		{name: "TopLeft", src: image.Rect(0, 0, 640, 480), p: image.Pt(0, 52), want: image.Pt(0, 0)},
+		{name: "BottomRight", src: image.Rect(0, 0, 640, 480), p: image.Pt(416, 364), want: image.Pt(640, 480)},
+		{name: "Center", src: image.Rect(0, 0, 640, 480), p: image.Pt(208, 208), want: image.Pt(320, 240)},
+		{name: "TopPadding", src: image.Rect(0, 0, 640, 480), p: image.Pt(13, 0), want: image.Pt(20, 0)},
+		{name: "BottomPadding", src: image.Rect(0, 0, 640, 480), p: image.Pt(416, 416), want: image.Pt(640, 480)},
+		{name: "SidePadding", src: image.Rect(0, 0, 480, 640), p: image.Pt(0, 208), want: image.Pt(0, 320)},
+		{name: "Offset", src: image.Rect(100, 50, 740, 530), p: image.Pt(208, 208), want: image.Pt(420, 290)},
+		{name: "Empty", src: image.Rect(10, 20, 10, 480), p: image.Pt(208, 208), want: image.Pt(10, 20)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l := NewLetterbox(tc.src, 416, 416)
+
+			if got := l.MapPoint(tc.p); got != tc.want {
+				t.Errorf("expected %v, got: %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLetterboxMapRect(t *testing.T) {
+	l := NewLetterbox(image.Rect(0, 0, 640, 480), 416, 416)
+
+	want := image.Rect(0, 0, 320, 240)
+	if got := l.MapRect(image.Rect(0, 0, 208, 208)); got != want {
+		t.Errorf("expected %v, got: %v", want, got)
+	}
+}
+
+func TestLetterboxMapBox(t *testing.T) {
+	tests := []struct {
+		name string
+		src  image.Rectangle
+		box  results.Box
+		want results.Box
+	}{
+		{
+			name: "Full",
+			src:  image.Rect(0, 0, 640, 480),
+			box:  results.Box{XMin: 0, YMin: 0.125, XMax: 1, YMax: 0.875},
+			want: results.Box{XMin: 0, YMin: 0, XMax: 1, YMax: 1},
+		},
+		{
+			name: "Padding",
+			src:  image.Rect(0, 0, 640, 480),
+			box:  results.Box{XMin: 0.25, YMin: 0, XMax: 0.75, YMax: 1},
+			want: results.Box{XMin: 0.25, YMin: 0, XMax: 0.75, YMax: 1},
+		},
+		{
+			name: "Center",
+			src:  image.Rect(0, 0, 480, 640),
+			box:  results.Box{XMin: 0.25, YMin: 0.25, XMax: 0.75, YMax: 0.75},
+			want: results.Box{XMin: 1.0 / 6, YMin: 0.25, XMax: 5.0 / 6, YMax: 0.75},
+		},
+		{
+			name: "Offset",
+			src:  image.Rect(100, 50, 740, 530),
+			box:  results.Box{XMin: 0, YMin: 0.125, XMax: 0.5, YMax: 0.5},
+			want: results.Box{XMin: 0, YMin: 0, XMax: 0.5, YMax: 0.5},
+		},
+		{
+			name: "Empty",
+			src:  image.Rect(0, 0, 640, 0),
+			box:  results.Box{XMin: 0.25, YMin: 0.25, XMax: 0.75, YMax: 0.75},
+			want: results.Box{},
+		},
+	}
+
+	near := func(a, b float32) bool {
+		return math.Abs(float64(a-b)) < 1e-6
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l := NewLetterbox(tc.src, 416, 416)
+
+			got := l.MapBox(tc.box)
+			if !near(got.XMin, tc.want.XMin) || !near(got.YMin, tc.want.YMin) ||
+				!near(got.XMax, tc.want.XMax) || !near(got.YMax, tc.want.YMax) {
+				t.Errorf("expected %+v, got: %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLetterboxMapDetections(t *testing.T) {
+	l := NewLetterbox(image.Rect(0, 0, 640, 480), 416, 416)
+
+	dets := results.Detections{
+		{Index: 1, Box: results.Box{XMin: 0, YMin: 0.125, XMax: 1, YMax: 0.875}},
+		{Index: 2, Box: results.Box{XMin: 0.5, YMin: 0.5, XMax: 1, YMax: 0.875}},
+	}
+	l.MapDetections(dets)
+
+	want := []results.Box{{XMin: 0, YMin: 0, XMax: 1, YMax: 1}, {XMin: 0.5, YMin: 0.5, XMax: 1, YMax: 1}}
+	for i, det := range dets {
+		if det.Box != want[i] {
+			t.Errorf("detection %d: expected %+v, got: %+v", i, want[i], det.Box)
+		}
+	}
+}
+
+func TestLetterboxApply(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	fill := color.RGBA{R: 128, G: 128, B: 128, A: 255}
+
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			src.SetRGBA(x, y, red)
+		}
+	}
+
+	l := NewLetterbox(src.Bounds(), 4, 4)
+	dst := l.Apply(src, fill)
+
+	if dst.Bounds() != image.Rect(0, 0, 4, 4) {
+		t.Fatalf("expected bounds %v, got: %v", image.Rect(0, 0, 4, 4), dst.Bounds())
+	}
+
+	for y := 0; y < 4; y++ {
+		want := fill
+		if y == 1 || y == 2 {
+			want = red
+		}
+
+		for x := 0; x < 4; x++ {
+			if got := dst.RGBAAt(x, y); got != want {
+				t.Errorf("pixel (%d,%d): expected %v, got: %v", x, y, want, got)
+			}
+		}
+	}
+}
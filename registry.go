@@ -0,0 +1,132 @@
+package ncs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OptionInfo describes NCSDK option
+type OptionInfo struct {
+	// Option is the option itself
+	Option Option `json:"-"`
+	// Resource is the type of the resource the option belongs to: global, device, graph or fifo
+	Resource string `json:"resource"`
+	// Name is the option name
+	Name string `json:"name"`
+	// Code is the NCSDK option code
+	Code int `json:"code"`
+	// Writable is true if the option can be set
+	Writable bool `json:"writable"`
+	// Type is the Go type the option data is decoded into
+	Type string `json:"type"`
+	// Size is the native size of a single option element in bytes
+	Size uint `json:"size"`
+}
+
+// optionRegistry contains the descriptions of all the NCSDK options
+var optionRegistry = buildOptionRegistry()
+
+// optionSizes maps resources to the native sizes of their options
+var optionSizes = map[string]map[Option]uint{
+	"global": globalOptSize,
+	"device": deviceOptSize,
+	"graph":  graphOptSize,
+	"fifo":   fifoOptSize,
+}
+
+// optionSpecs declares the Go type each option decodes into and whether it can be set via this package,
+// ordered by resource and option code
+var optionSpecs = []struct {
+	resource string
+	opt      Option
+	typ      string
+	writable bool
+}{
+	{"global", RWGlobalLogLevel, "LogLevel", true},
+	{"global", ROGlobalAPIVersion, "[]uint32", false},
+
+	{"device", RODeviceThermalStats, "[]float32", false},
+	{"device", RODeviceThermalThrottle, "uint", false},
+	{"device", RODeviceState, "uint", false},
+	{"device", RODeviceMemoryUsed, "uint", false},
+	{"device", RODeviceMemorySize, "uint", false},
+	{"device", RODeviceMaxFifoCount, "uint", false},
+	{"device", RODeviceAllocatedFifoCount, "uint", false},
+	{"device", RODeviceMaxGraphCount, "uint", false},
+	{"device", RODeviceAllocatedGraphCount, "uint", false},
+	{"device", RODeviceClassLimit, "uint", false},
+	{"device", RODeviceFirmwareVersion, "[]uint32", false},
+	{"device", RODeviceDebugInfo, "string", false},
+	{"device", RODeviceMVTensorVersion, "[]uint32", false},
+	{"device", RODeviceName, "string", false},
+	{"device", RODeviceMaxExecutors, "uint", false},
+	{"device", RODeviceHWVersion, "uint", false},
+
+	{"graph", ROGraphState, "uint", false},
+	{"graph", ROGraphInferenceTime, "[]float32", false},
+	{"graph", ROGraphInputCount, "uint", false},
+	{"graph", ROGraphOutputCount, "uint", false},
+	{"graph", ROGraphInputTensorDesc, "[]TensorDesc", false},
+	{"graph", ROGraphOutputTensorDesc, "[]TensorDesc", false},
+	{"graph", ROGraphDebugInfo, "string", false},
+	{"graph", ROGraphName, "string", false},
+	{"graph", ROGraphOptionClassLimit, "uint", false},
+	{"graph", ROGraphVersion, "[]uint32", false},
+	// NCSDK does not implement setting the executors count yet
+	{"graph", RWGraphExecutorsCount, "uint", false},
+	{"graph", ROGraphInferenceTimeSize, "uint", false},
+
+	{"fifo", RWFifoType, "uint", true},
+	{"fifo", RWFifoConsumerCount, "uint", true},
+	{"fifo", RWFifoDataType, "uint", true},
+	// NCSDK does not implement non-blocking FIFOs yet
+	{"fifo", RWFifoNoBlock, "uint", false},
+	{"fifo", ROFifoCapacity, "uint", false},
+	{"fifo", ROFifoReadFillLevel, "uint", false},
+	{"fifo", ROFifoWriteFillLevel, "uint", false},
+	{"fifo", ROFifoGraphTensorDesc, "*TensorDesc", false},
+	{"fifo", ROFifoState, "uint", false},
+	{"fifo", ROFifoName, "string", false},
+	{"fifo", ROFifoElemDataSize, "uint", false},
+	{"fifo", RWFifoHostTensorDesc, "*TensorDesc", true},
+}
+
+// buildOptionRegistry returns the descriptions of all the NCSDK options ordered by resource and code
+func buildOptionRegistry() []OptionInfo {
+	opts := make([]OptionInfo, len(optionSpecs))
+
+	for i, spec := range optionSpecs {
+		opts[i] = OptionInfo{
+			Option:   spec.opt,
+			Resource: spec.resource,
+			Name:     fmt.Sprint(spec.opt),
+			Code:     spec.opt.Value(),
+			Writable: spec.writable,
+			Type:     spec.typ,
+			Size:     optionSizes[spec.resource][spec.opt],
+		}
+	}
+
+	return opts
+}
+
+// Options returns the descriptions of all the NCSDK options ordered by resource and option code.
+// It allows generic tools to list, query and decode options without hardcoding them.
+func Options() []OptionInfo {
+	opts := make([]OptionInfo, len(optionRegistry))
+	copy(opts, optionRegistry)
+
+	return opts
+}
+
+// LookupOption returns the description of the option with the given name which belongs to resource.
+// The name is matched case insensitively. It returns false if no such option exists.
+func LookupOption(resource, name string) (OptionInfo, bool) {
+	for _, info := range optionRegistry {
+		if info.Resource == resource && strings.EqualFold(info.Name, name) {
+			return info, true
+		}
+	}
+
+	return OptionInfo{}, false
+}
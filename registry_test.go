@@ -0,0 +1,79 @@
+package ncs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestOptionRegistryTypes(t *testing.T) {
+	for _, info := range Options() {
+		t.Run(info.Name, func(t *testing.T) {
+			val, err := info.Option.Decode(make([]byte, 4096))
+			if err != nil {
+				t.Fatalf("failed to decode option: %v", err)
+			}
+
+			if typ := strings.ReplaceAll(fmt.Sprintf("%T", val), "ncs.", ""); typ != info.Type {
+				t.Errorf("expected type %s, got: %s", info.Type, typ)
+			}
+
+			if info.Size == 0 {
+				t.Errorf("expected non-zero option size")
+			}
+		})
+	}
+}
+
+func TestOptionRegistryComplete(t *testing.T) {
+	// every global, device, graph and FIFO option is registered
+	want := 2 + int(RODeviceHWVersion-RODeviceThermalStats+1) +
+		int(ROGraphInferenceTimeSize-ROGraphState+1) + int(RWFifoHostTensorDesc-RWFifoType+1)
+
+	if n := len(Options()); n != want {
+		t.Errorf("expected %d options, got: %d", want, n)
+	}
+}
+
+func TestOptionRegistryWritable(t *testing.T) {
+	for _, info := range Options() {
+		var err error
+
+		switch opt := info.Option.(type) {
+		case GlobalOption:
+			if opt != RWGlobalLogLevel {
+				err = SetGlobalOption(opt, nil)
+			}
+		case FifoOption:
+			err = validateFifoOption(opt, nil)
+		default:
+			// devices and graphs have no settable options
+			if info.Writable {
+				t.Errorf("option %s can not be set", info.Name)
+			}
+			continue
+		}
+
+		// writable options fail validation on the nil value, the others are rejected outright
+		rejected := err != nil && (strings.Contains(err.Error(), "read only") || errors.Is(err, ErrUnsupportedFeature))
+		if info.Writable == rejected {
+			t.Errorf("option %s: expected writable %v, got setter error: %v", info.Name, info.Writable, err)
+		}
+	}
+}
+
+func TestLookupOption(t *testing.T) {
+	info, ok := LookupOption("fifo", "rw_fifo_host_tensor_descriptor")
+	if !ok {
+		t.Fatalf("option not found")
+	}
+
+	if info.Option != RWFifoHostTensorDesc || !info.Writable || info.Type != "*TensorDesc" {
+		t.Errorf("unexpected option info: %+v", info)
+	}
+
+	if _, ok := LookupOption("graph", "rw_fifo_host_tensor_descriptor"); ok {
+		t.Errorf("expected option of another resource not to be found")
+	}
+}
@@ -0,0 +1,336 @@
+// Package report generates self-contained HTML reports of batch inference runs.
+// The reports embed annotated image thumbnails, per-image predictions, confidence histograms
+// and timing statistics, so they can be archived or shared as a single file.
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"image"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/milosgajdos/ncs/draw"
+	"github.com/milosgajdos/ncs/results"
+	xdraw "golang.org/x/image/draw"
+)
+
+// Item is a result of a single inference of a batch run
+type Item struct {
+	// Name identifies the input, usually the image file path
+	Name string
+	// Image is the input image. Its thumbnail is omitted from the report if it is nil.
+	Image image.Image
+	// Predictions are the predictions of classification models
+	Predictions results.Results
+	// Detections are the detections of detection models, drawn onto the thumbnail
+	Detections results.Detections
+	// Duration is the inference duration
+	Duration time.Duration
+	// Err is the error the inference failed with, if any
+	Err error
+}
+
+// Opts configures the report
+type Opts struct {
+	// Title is the report title. It defaults to "Inference report".
+	Title string
+	// ThumbSize is the maximum thumbnail width and height in pixels. It defaults to 224.
+	ThumbSize int
+	// Top is the number of top predictions listed for each image. It defaults to 5.
+	Top int
+	// Bins is the number of confidence histogram bins. It defaults to 10.
+	Bins int
+	// Draw configures drawing of detections onto the thumbnails
+	Draw *draw.Opts
+}
+
+// defaults returns a copy of opts with the unset options set to their defaults
+func defaults(opts *Opts) Opts {
+	var o Opts
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Title == "" {
+		o.Title = "Inference report"
+	}
+	if o.ThumbSize <= 0 {
+		o.ThumbSize = 224
+	}
+	if o.Top <= 0 {
+		o.Top = 5
+	}
+	if o.Bins <= 0 {
+		o.Bins = 10
+	}
+
+	return o
+}
+
+// Stats are timing statistics of a batch run
+type Stats struct {
+	// Count is the number of inferences
+	Count int
+	// Errors is the number of failed inferences
+	Errors int
+	// Total is the total duration of all inferences
+	Total time.Duration
+	// Min is the shortest inference duration
+	Min time.Duration
+	// Max is the longest inference duration
+	Max time.Duration
+	// Mean is the mean inference duration
+	Mean time.Duration
+	// P50 is the median inference duration
+	P50 time.Duration
+	// P95 is the 95th percentile of inference durations
+	P95 time.Duration
+}
+
+// NewStats computes the timing statistics of items. The failed inferences are not included in durations.
+func NewStats(items []Item) Stats {
+	s := Stats{Count: len(items)}
+
+	var durs []time.Duration
+	for _, item := range items {
+		if item.Err != nil {
+			s.Errors++
+			continue
+		}
+		durs = append(durs, item.Duration)
+		s.Total += item.Duration
+	}
+
+	if len(durs) == 0 {
+		return s
+	}
+
+	sort.Slice(durs, func(i, j int) bool { return durs[i] < durs[j] })
+
+	s.Min, s.Max = durs[0], durs[len(durs)-1]
+	s.Mean = s.Total / time.Duration(len(durs))
+	s.P50 = percentile(durs, 0.50)
+	s.P95 = percentile(durs, 0.95)
+
+	return s
+}
+
+// percentile returns the p-th percentile of sorted durations using the nearest-rank method
+func percentile(durs []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(durs)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(durs) {
+		rank = len(durs) - 1
+	}
+
+	return durs[rank]
+}
+
+// Bin is a confidence histogram bin
+type Bin struct {
+	// Low is the inclusive lower bound of the bin
+	Low float32
+	// High is the exclusive upper bound of the bin, the last bin includes it
+	High float32
+	// Count is the number of scores in the bin
+	Count int
+}
+
+// Histogram returns the histogram of the confidence scores of items split into n equally sized bins over [0, 1].
+// The top prediction score of each image is counted for classification models and the score of every detection
+// for detection models. The scores outside of [0, 1] are clamped to the closest bin.
+func Histogram(items []Item, n int) []Bin {
+	if n <= 0 {
+		return nil
+	}
+
+	bins := make([]Bin, n)
+	for i := range bins {
+		bins[i].Low = float32(i) / float32(n)
+		bins[i].High = float32(i+1) / float32(n)
+	}
+
+	add := func(score float32) {
+		i := int(score * float32(n))
+		if i < 0 {
+			i = 0
+		}
+		if i >= n {
+			i = n - 1
+		}
+		bins[i].Count++
+	}
+
+	for _, item := range items {
+		if item.Err != nil {
+			continue
+		}
+		if top := item.Predictions.Top(1); len(top) > 0 {
+			add(top[0].Score)
+		}
+		for _, det := range item.Detections {
+			add(det.Score)
+		}
+	}
+
+	return bins
+}
+
+// thumbnail returns PNG data URI of img scaled down to fit into size x size with dets drawn onto it
+func thumbnail(img image.Image, dets results.Detections, size int, opts *draw.Opts) (template.URL, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return "", fmt.Errorf("Empty image")
+	}
+
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, h*size/w
+		} else {
+			w, h = w*size/h, size
+		}
+		if w == 0 {
+			w = 1
+		}
+		if h == 0 {
+			h = 1
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, b, xdraw.Src, nil)
+	draw.Detections(dst, dets, opts)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return "", err
+	}
+
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+// entry is a report item as rendered by the HTML template
+type entry struct {
+	Item
+	Thumb template.URL
+	Top   results.Results
+}
+
+// bar is a histogram bin as rendered by the HTML template
+type bar struct {
+	Bin
+	Percent float64
+}
+
+// page is the data rendered by the HTML template
+type page struct {
+	Title     string
+	Generated time.Time
+	Stats     Stats
+	Histogram []bar
+	Entries   []entry
+}
+
+// Write writes the HTML report of the batch run items to w. If opts is nil the default options are used.
+// It returns error if it fails to generate a thumbnail or to write the report.
+func Write(w io.Writer, items []Item, opts *Opts) error {
+	o := defaults(opts)
+
+	p := page{
+		Title:     o.Title,
+		Generated: time.Now(),
+		Stats:     NewStats(items),
+	}
+
+	bins := Histogram(items, o.Bins)
+	max := 0
+	for _, b := range bins {
+		if b.Count > max {
+			max = b.Count
+		}
+	}
+	for _, b := range bins {
+		var pct float64
+		if max > 0 {
+			pct = 100 * float64(b.Count) / float64(max)
+		}
+		p.Histogram = append(p.Histogram, bar{Bin: b, Percent: pct})
+	}
+
+	for _, item := range items {
+		e := entry{Item: item, Top: item.Predictions.Top(o.Top)}
+		if item.Image != nil {
+			thumb, err := thumbnail(item.Image, item.Detections, o.ThumbSize, o.Draw)
+			if err != nil {
+				return fmt.Errorf("Thumbnail %s: %w", item.Name, err)
+			}
+			e.Thumb = thumb
+		}
+		p.Entries = append(p.Entries, e)
+	}
+
+	return tmpl.Execute(w, p)
+}
+
+// tmpl is the report HTML template
+var tmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pct": func(f float32) string { return fmt.Sprintf("%.1f%%", 100*f) },
+	"ms":  func(d time.Duration) string { return fmt.Sprintf("%.2f ms", float64(d)/float64(time.Millisecond)) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+td, th { padding: 4px 8px; border-bottom: 1px solid #ddd; text-align: left; vertical-align: top; }
+.hist { display: flex; align-items: flex-end; height: 120px; gap: 4px; }
+.hist div { flex: 1; background: #4a90d9; min-height: 1px; }
+.hist-labels { display: flex; gap: 4px; font-size: 0.75em; }
+.hist-labels span { flex: 1; text-align: center; }
+.error { color: #c00; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Generated {{.Generated.Format "2006-01-02 15:04:05 MST"}}</p>
+
+<h2>Timing</h2>
+<table>
+<tr><th>Inferences</th><td>{{.Stats.Count}}</td></tr>
+<tr><th>Errors</th><td>{{.Stats.Errors}}</td></tr>
+<tr><th>Total</th><td>{{ms .Stats.Total}}</td></tr>
+<tr><th>Min</th><td>{{ms .Stats.Min}}</td></tr>
+<tr><th>Mean</th><td>{{ms .Stats.Mean}}</td></tr>
+<tr><th>P50</th><td>{{ms .Stats.P50}}</td></tr>
+<tr><th>P95</th><td>{{ms .Stats.P95}}</td></tr>
+<tr><th>Max</th><td>{{ms .Stats.Max}}</td></tr>
+</table>
+
+<h2>Confidence</h2>
+<div class="hist">{{range .Histogram}}<div style="height: {{printf "%.1f" .Percent}}%" title="{{.Count}}"></div>{{end}}</div>
+<div class="hist-labels">{{range .Histogram}}<span>{{printf "%.1f" .Low}}-{{printf "%.1f" .High}}<br>{{.Count}}</span>{{end}}</div>
+
+<h2>Images</h2>
+<table>
+<tr><th>Image</th><th>Name</th><th>Results</th><th>Time</th></tr>
+{{range .Entries}}<tr>
+<td>{{if .Thumb}}<img src="{{.Thumb}}" alt="{{.Name}}">{{end}}</td>
+<td>{{.Name}}</td>
+<td>{{if .Err}}<span class="error">{{.Err}}</span>{{else}}<ol>{{range .Top}}<li>{{.Label}} ({{.Index}}) {{pct .Score}}</li>{{end}}{{range .Detections}}<li>{{.Label}} ({{.Index}}) {{pct .Score}}</li>{{end}}</ol>{{end}}</td>
+<td>{{if not .Err}}{{ms .Duration}}{{end}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,156 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/ncs/results"
+)
+
+// durations returns items with durations of 1 to n milliseconds in reverse order
+func durations(n int) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		items[i].Duration = time.Duration(n-i) * time.Millisecond
+	}
+
+	return items
+}
+
+func TestNewStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []Item
+		want  Stats
+	}{
+		{name: "Empty", want: Stats{}},
+		{name: "One", items: durations(1), want: Stats{Count: 1, Total: 1 * time.Millisecond, Min: 1 * time.Millisecond,
+			Max: 1 * time.Millisecond, Mean: 1 * time.Millisecond, P50: 1 * time.Millisecond, P95: 1 * time.Millisecond}},
+		{name: "Two", items: durations(2), want: Stats{Count: 2, Total: 3 * time.Millisecond, Min: 1 * time.Millisecond,
+			Max: 2 * time.Millisecond, Mean: 1500 * time.Microsecond, P50: 1 * time.Millisecond, P95: 2 * time.Millisecond}},
+		{name: "Three", items: durations(3), want: Stats{Count: 3, Total: 6 * time.Millisecond, Min: 1 * time.Millisecond,
+			Max: 3 * time.Millisecond, Mean: 2 * time.Millisecond, P50: 2 * time.Millisecond, P95: 3 * time.Millisecond}},
+		{name: "Eleven", items: durations(11), want: Stats{Count: 11, Total: 66 * time.Millisecond, Min: 1 * time.Millisecond,
+			Max: 11 * time.Millisecond, Mean: 6 * time.Millisecond, P50: 6 * time.Millisecond, P95: 11 * time.Millisecond}},
+		{name: "Twenty", items: durations(20), want: Stats{Count: 20, Total: 210 * time.Millisecond, Min: 1 * time.Millisecond,
+			Max: 20 * time.Millisecond, Mean: 10500 * time.Microsecond, P50: 10 * time.Millisecond, P95: 19 * time.Millisecond}},
+		{name: "Hundred", items: durations(100), want: Stats{Count: 100, Total: 5050 * time.Millisecond, Min: 1 * time.Millisecond,
+			Max: 100 * time.Millisecond, Mean: 50500 * time.Microsecond, P50: 50 * time.Millisecond, P95: 95 * time.Millisecond}},
+		{name: "Errors", items: append(durations(2), Item{Duration: time.Hour, Err: errors.New("failed")}),
+			want: Stats{Count: 3, Errors: 1, Total: 3 * time.Millisecond, Min: 1 * time.Millisecond,
+				Max: 2 * time.Millisecond, Mean: 1500 * time.Microsecond, P50: 1 * time.Millisecond, P95: 2 * time.Millisecond}},
+		{name: "AllErrors", items: []Item{{Duration: time.Second, Err: errors.New("failed")}}, want: Stats{Count: 1, Errors: 1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NewStats(tc.items); got != tc.want {
+				t.Errorf("expected %+v, got: %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	items := []Item{
+		{Predictions: results.New([]float32{0.1, 0.55, 0.2}, nil)},
+		{Predictions: results.New([]float32{0.95}, nil)},
+		{Detections: results.Detections{{Score: 0.3}, {Score: 1}, {Score: -0.2}, {Score: 1.5}}},
+		{Predictions: results.New([]float32{0.3}, nil), Err: errors.New("failed")},
+	}
+
+	tests := []struct {
+		name   string
+		n      int
+		counts []int
+	}{
+		{name: "Zero", n: 0},
+		{name: "Negative", n: -1},
+		{name: "One", n: 1, counts: []int{6}},
+		{name: "Two", n: 2, counts: []int{2, 4}},
+		{name: "Four", n: 4, counts: []int{1, 1, 1, 3}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bins := Histogram(items, tc.n)
+
+			var counts []int
+			for i, b := range bins {
+				if low, high := float32(i)/float32(tc.n), float32(i+1)/float32(tc.n); b.Low != low || b.High != high {
+					t.Errorf("bin %d: expected [%v, %v), got: [%v, %v)", i, low, high, b.Low, b.High)
+				}
+				counts = append(counts, b.Count)
+			}
+
+			if !reflect.DeepEqual(counts, tc.counts) {
+				t.Errorf("expected counts %v, got: %v", tc.counts, counts)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	items := []Item{
+		{
+			Name:        "cat.jpg",
+			Image:       image.NewRGBA(image.Rect(0, 0, 640, 480)),
+			Predictions: results.New([]float32{0.1, 0.9}, []string{"dog", "cat"}),
+			Duration:    5 * time.Millisecond,
+		},
+		{
+			Name:       "<street>.jpg",
+			Image:      image.NewRGBA(image.Rect(0, 0, 100, 100)),
+			Detections: results.Detections{{Index: 1, Label: "car", Score: 0.8, Box: results.Box{XMax: 0.5, YMax: 0.5}}},
+			Duration:   7 * time.Millisecond,
+		},
+		{Name: "broken.jpg", Err: errors.New("read failed")},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, items, &Opts{Title: "Nightly run", ThumbSize: 64, Top: 1}); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<title>Nightly run</title>",
+		"cat (1) 90.0%",
+		"car (1) 80.0%",
+		"&lt;street&gt;.jpg",
+		`<span class="error">read failed</span>`,
+		"<tr><th>Errors</th><td>1</td></tr>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected report to contain %q", want)
+		}
+	}
+
+	if strings.Contains(out, "dog") {
+		t.Errorf("expected only the top prediction in report")
+	}
+
+	if n := strings.Count(out, `<img src="data:image/png;base64,`); n != 2 {
+		t.Errorf("expected 2 embedded thumbnails, got: %d", n)
+	}
+
+	// the report must not reference any external resources
+	for _, ref := range []string{"http://", "https://", "<link", "<script", "url("} {
+		if strings.Contains(out, ref) {
+			t.Errorf("expected self-contained report, found %q", ref)
+		}
+	}
+}
+
+func TestWriteEmptyImage(t *testing.T) {
+	items := []Item{{Name: "empty.jpg", Image: image.NewRGBA(image.Rect(0, 0, 0, 10))}}
+
+	if err := Write(&bytes.Buffer{}, items, nil); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
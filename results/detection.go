@@ -0,0 +1,91 @@
+package results
+
+import (
+	"encoding/csv"
+	"image"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Box is a bounding box of a detected object.
+// Its coordinates are normalized to [0,1] interval relative to the image width and height.
+type Box struct {
+	XMin float32 `json:"x_min"`
+	YMin float32 `json:"y_min"`
+	XMax float32 `json:"x_max"`
+	YMax float32 `json:"y_max"`
+}
+
+// Rect returns the box in pixel coordinates of an image of the given width and height.
+// The coordinates are clamped to the image bounds.
+func (b Box) Rect(width, height int) image.Rectangle {
+	px := func(v float32, size int) int {
+		p := int(math.Round(float64(v) * float64(size)))
+		if p < 0 {
+			return 0
+		}
+		if p > size {
+			return size
+		}
+		return p
+	}
+
+	return image.Rect(px(b.XMin, width), px(b.YMin, height), px(b.XMax, width), px(b.YMax, height))
+}
+
+// Detection is an object detected by a detection model.
+// Its JSON encoding always carries the box in normalized coordinates, as the pixel coordinates depend
+// on the size of the image, which the detection does not know. Use Box.Rect to get the pixel coordinates,
+// or WriteCSV which encodes both forms.
+type Detection struct {
+	// Index is the class index of the detected object
+	Index int `json:"index"`
+	// Label is the class label of the detected object, it is empty if the label is not known
+	Label string `json:"label"`
+	// Score is the detection confidence
+	Score float32 `json:"score"`
+	// Box is the bounding box of the detected object in normalized coordinates
+	Box Box `json:"box"`
+}
+
+// Detections are objects detected in a single image
+type Detections []Detection
+
+// WriteCSV writes the detections to w as CSV records. Every record contains the class index, label, score
+// and the normalized box coordinates followed by the box coordinates in pixels of an image of the given
+// width and height. The pixel coordinates are left empty if width or height is not positive.
+func (d Detections) WriteCSV(w io.Writer, width, height int) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{
+		"index", "label", "score",
+		"x_min", "y_min", "x_max", "y_max",
+		"px_x_min", "px_y_min", "px_x_max", "px_y_max",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, det := range d {
+		rec := []string{
+			strconv.Itoa(det.Index), det.Label, formatFloat(det.Score),
+			formatFloat(det.Box.XMin), formatFloat(det.Box.YMin), formatFloat(det.Box.XMax), formatFloat(det.Box.YMax),
+			"", "", "", "",
+		}
+
+		if width > 0 && height > 0 {
+			r := det.Box.Rect(width, height)
+			rec[7], rec[8] = strconv.Itoa(r.Min.X), strconv.Itoa(r.Min.Y)
+			rec[9], rec[10] = strconv.Itoa(r.Max.X), strconv.Itoa(r.Max.Y)
+		}
+
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,98 @@
+// Package results provides types which represent the results of classification and detection models.
+package results
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Prediction is a single class prediction of a classification model
+type Prediction struct {
+	// Index is the class index
+	Index int `json:"index"`
+	// Label is the class label, it is empty if the label is not known
+	Label string `json:"label"`
+	// Score is the class score, usually its probability
+	Score float32 `json:"score"`
+}
+
+// Results are class predictions of a classification model
+type Results []Prediction
+
+// New returns Results created from the class scores as returned by a classification model.
+// The predictions are labelled with labels indexed by the class index and they are ordered by the class index.
+func New(scores []float32, labels []string) Results {
+	res := make(Results, len(scores))
+	for i, score := range scores {
+		res[i] = Prediction{Index: i, Score: score}
+		if i < len(labels) {
+			res[i].Label = labels[i]
+		}
+	}
+
+	return res
+}
+
+// Sorted returns a copy of the results sorted by score in descending order.
+// The sort is stable: predictions with equal scores keep their original order.
+func (r Results) Sorted() Results {
+	res := make(Results, len(r))
+	copy(res, r)
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+
+	return res
+}
+
+// Top returns k predictions with the highest scores sorted by score in descending order.
+// It returns all the predictions if k is larger than their count.
+func (r Results) Top(k int) Results {
+	res := r.Sorted()
+	if k < 0 {
+		k = 0
+	}
+	if k < len(res) {
+		res = res[:k]
+	}
+
+	return res
+}
+
+// Above returns the predictions whose score is at least threshold sorted by score in descending order
+func (r Results) Above(threshold float32) Results {
+	res := r.Sorted()
+
+	n := sort.Search(len(res), func(i int) bool {
+		return res[i].Score < threshold
+	})
+
+	return res[:n]
+}
+
+// WriteCSV writes the predictions to w as CSV records with index,label,score header
+func (r Results) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"index", "label", "score"}); err != nil {
+		return err
+	}
+
+	for _, p := range r {
+		rec := []string{strconv.Itoa(p.Index), p.Label, formatFloat(p.Score)}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// formatFloat formats f using the shortest representation which round trips
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'g', -1, 32)
+}
@@ -0,0 +1,258 @@
+package results
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"image"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// indices returns the class indices of the predictions in r
+func indices(r Results) []int {
+	idx := make([]int, len(r))
+	for i, p := range r {
+		idx[i] = p.Index
+	}
+
+	return idx
+}
+
+func TestNew(t *testing.T) {
+	res := New([]float32{0.1, 0.7, 0.2}, []string{"cat", "dog"})
+
+	want := Results{
+		{Index: 0, Label: "cat", Score: 0.1},
+		{Index: 1, Label: "dog", Score: 0.7},
+		{Index: 2, Label: "", Score: 0.2},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("expected %v, got: %v", want, res)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	// equal scores must keep their class index order
+	res := New([]float32{0.2, 0.5, 0.2, 0.5, 0.1}, nil)
+
+	sorted := res.Sorted()
+	if want := []int{1, 3, 0, 2, 4}; !reflect.DeepEqual(indices(sorted), want) {
+		t.Errorf("expected order %v, got: %v", want, indices(sorted))
+	}
+
+	if want := []int{0, 1, 2, 3, 4}; !reflect.DeepEqual(indices(res), want) {
+		t.Errorf("expected original results not to be modified, got: %v", indices(res))
+	}
+}
+
+func TestTop(t *testing.T) {
+	res := New([]float32{0.2, 0.5, 0.2, 0.5, 0.1}, nil)
+
+	tests := []struct {
+		name string
+		k    int
+		want []int
+	}{
+		{name: "Negative", k: -1, want: []int{}},
+		{name: "Zero", k: 0, want: []int{}},
+		{name: "Tie", k: 1, want: []int{1}},
+		{name: "TieBoundary", k: 3, want: []int{1, 3, 0}},
+		{name: "All", k: 5, want: []int{1, 3, 0, 2, 4}},
+		{name: "MoreThanAll", k: 10, want: []int{1, 3, 0, 2, 4}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := indices(res.Top(tc.k)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %v, got: %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAbove(t *testing.T) {
+	res := New([]float32{0.2, 0.5, 0.2, 0.5, 0.1}, nil)
+
+	tests := []struct {
+		name      string
+		threshold float32
+		want      []int
+	}{
+		{name: "AboveAll", threshold: 0.6, want: []int{}},
+		{name: "EqualTie", threshold: 0.5, want: []int{1, 3}},
+		{name: "BetweenTies", threshold: 0.3, want: []int{1, 3}},
+		{name: "EqualLowerTie", threshold: 0.2, want: []int{1, 3, 0, 2}},
+		{name: "BelowAll", threshold: 0, want: []int{1, 3, 0, 2, 4}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := indices(res.Above(tc.threshold)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %v, got: %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestResultsJSON(t *testing.T) {
+	res := New([]float32{0.25, 0.75}, []string{"cat", "dog"})
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("failed to encode results: %v", err)
+	}
+
+	if want := `[{"index":0,"label":"cat","score":0.25},{"index":1,"label":"dog","score":0.75}]`; string(data) != want {
+		t.Errorf("expected %s, got: %s", want, data)
+	}
+
+	var got Results
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to decode results: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, res) {
+		t.Errorf("expected %v, got: %v", res, got)
+	}
+}
+
+func TestResultsCSV(t *testing.T) {
+	res := New([]float32{0.1, 1.0 / 3}, []string{"cat", "dog, hound"})
+
+	var buf bytes.Buffer
+	if err := res.WriteCSV(&buf); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	recs, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+
+	if want := []string{"index", "label", "score"}; !reflect.DeepEqual(recs[0], want) {
+		t.Errorf("expected header %v, got: %v", want, recs[0])
+	}
+
+	var got Results
+	for _, rec := range recs[1:] {
+		index, _ := strconv.Atoi(rec[0])
+		score, _ := strconv.ParseFloat(rec[2], 32)
+		got = append(got, Prediction{Index: index, Label: rec[1], Score: float32(score)})
+	}
+
+	if !reflect.DeepEqual(got, res) {
+		t.Errorf("expected %v, got: %v", res, got)
+	}
+}
+
+func TestBoxRect(t *testing.T) {
+	tests := []struct {
+		name string
+		box  Box
+		want image.Rectangle
+	}{
+		{name: "Inside", box: Box{XMin: 0.25, YMin: 0.5, XMax: 0.75, YMax: 1}, want: image.Rect(160, 240, 480, 480)},
+		{name: "Clamped", box: Box{XMin: -0.1, YMin: -1, XMax: 1.5, YMax: 2}, want: image.Rect(0, 0, 640, 480)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.box.Rect(640, 480); got != tc.want {
+				t.Errorf("expected %v, got: %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDetectionsJSON(t *testing.T) {
+	dets := Detections{
+		{Index: 15, Label: "person", Score: 0.875, Box: Box{XMin: 0.125, YMin: 0.25, XMax: 0.5, YMax: 1}},
+	}
+
+	data, err := json.Marshal(dets)
+	if err != nil {
+		t.Fatalf("failed to encode detections: %v", err)
+	}
+
+	want := `[{"index":15,"label":"person","score":0.875,"box":{"x_min":0.125,"y_min":0.25,"x_max":0.5,"y_max":1}}]`
+	if string(data) != want {
+		t.Errorf("expected %s, got: %s", want, data)
+	}
+
+	var got Detections
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to decode detections: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, dets) {
+		t.Errorf("expected %v, got: %v", dets, got)
+	}
+}
+
+func TestDetectionsCSV(t *testing.T) {
+	dets := Detections{
+		{Index: 15, Label: "person", Score: 0.875, Box: Box{XMin: 0.125, YMin: 0.25, XMax: 0.5, YMax: 1}},
+		{Index: 7, Label: "car", Score: 0.5, Box: Box{XMin: 0, YMin: 0, XMax: 0.25, YMax: 0.5}},
+	}
+
+	tests := []struct {
+		name          string
+		width, height int
+		pixels        [][]string
+	}{
+		{name: "Pixels", width: 640, height: 480, pixels: [][]string{{"80", "120", "320", "480"}, {"0", "0", "160", "240"}}},
+		{name: "NoSize", width: 0, height: 480, pixels: [][]string{{"", "", "", ""}, {"", "", "", ""}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := dets.WriteCSV(&buf, tc.width, tc.height); err != nil {
+				t.Fatalf("failed to write CSV: %v", err)
+			}
+
+			recs, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("failed to read CSV: %v", err)
+			}
+
+			header := []string{"index", "label", "score", "x_min", "y_min", "x_max", "y_max", "px_x_min", "px_y_min", "px_x_max", "px_y_max"}
+			if !reflect.DeepEqual(recs[0], header) {
+				t.Errorf("expected header %v, got: %v", header, recs[0])
+			}
+
+			if len(recs) != len(dets)+1 {
+				t.Fatalf("expected %d records, got: %d", len(dets)+1, len(recs))
+			}
+
+			for i, rec := range recs[1:] {
+				parse := func(s string) float32 {
+					f, err := strconv.ParseFloat(s, 32)
+					if err != nil {
+						t.Fatalf("failed to parse %q: %v", s, err)
+					}
+					return float32(f)
+				}
+
+				index, _ := strconv.Atoi(rec[0])
+				got := Detection{
+					Index: index,
+					Label: rec[1],
+					Score: parse(rec[2]),
+					Box:   Box{XMin: parse(rec[3]), YMin: parse(rec[4]), XMax: parse(rec[5]), YMax: parse(rec[6])},
+				}
+
+				if got != dets[i] {
+					t.Errorf("expected %v, got: %v", dets[i], got)
+				}
+
+				if !reflect.DeepEqual(rec[7:], tc.pixels[i]) {
+					t.Errorf("expected pixel coordinates %v, got: %v", tc.pixels[i], rec[7:])
+				}
+			}
+		})
+	}
+}
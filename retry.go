@@ -0,0 +1,71 @@
+package ncs
+
+// #cgo LDFLAGS: -lmvnc
+/*
+#include <ncs.h>
+*/
+import "C"
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configures retries of NCSDK API calls which fail with transient status codes.
+// Only the calls which return StatusBusy or StatusTimeout are retried.
+// The calls abandoned because their timeout expired are never retried, see ErrCallAbandoned.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts, including the first call
+	Attempts int
+	// Backoff is the delay before the first retry. It doubles with every subsequent retry.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between retries. Zero value means no cap.
+	MaxBackoff time.Duration
+	// Jitter is the fraction of the delay which is randomized. It must be in [0,1] interval.
+	Jitter float64
+}
+
+// transient returns true if the status s is worth retrying
+func transient(s Status) bool {
+	return s == StatusBusy || s == StatusTimeout
+}
+
+// delay returns the backoff delay before the given retry attempt
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff << uint(attempt)
+	if d < 0 || (p.MaxBackoff > 0 && d > p.MaxBackoff) {
+		d = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 && d > 0 {
+		j := time.Duration(p.Jitter * float64(d))
+		d = d - j + time.Duration(rand.Int63n(int64(2*j)+1))
+	}
+
+	return d
+}
+
+// do calls fn and retries it according to the policy if it returns a transient status.
+// It returns the status of the last call. If p is nil fn is called exactly once.
+// It stops retrying and returns error if fn returns error or if ctx is done.
+func (p *RetryPolicy) do(ctx context.Context, fn func() (C.int, error)) (C.int, error) {
+	s, err := fn()
+
+	if p == nil {
+		return s, err
+	}
+
+	for attempt := 1; err == nil && attempt < p.Attempts && transient(Status(s)); attempt++ {
+		timer := time.NewTimer(p.delay(attempt - 1))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return s, ctx.Err()
+		}
+
+		s, err = fn()
+	}
+
+	return s, err
+}
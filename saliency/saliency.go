@@ -0,0 +1,136 @@
+// Package saliency computes saliency maps which show the image regions a model responds to.
+// The maps can be rendered and overlaid onto the input images with the draw package.
+package saliency
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// ScoreFunc runs the inference of img and returns the score of the class of interest,
+// e.g. the score of the top class of the unoccluded image.
+type ScoreFunc func(img image.Image) (float32, error)
+
+// Opts configures occlusion saliency
+type Opts struct {
+	// Patch is the occluding patch size in pixels. It defaults to 1/8 of the shorter image side.
+	Patch int
+	// Stride is the patch stride in pixels. It defaults to Patch.
+	Stride int
+	// Fill is the occluding patch color. It defaults to mid gray.
+	Fill color.Color
+}
+
+// defaults returns a copy of opts with the unset options set to their defaults for image bounds b
+func defaults(opts *Opts, b image.Rectangle) Opts {
+	var o Opts
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Patch <= 0 {
+		side := b.Dx()
+		if b.Dy() < side {
+			side = b.Dy()
+		}
+		o.Patch = side / 8
+		if o.Patch < 1 {
+			o.Patch = 1
+		}
+	}
+	if o.Stride <= 0 {
+		o.Stride = o.Patch
+	}
+	if o.Fill == nil {
+		o.Fill = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+	}
+
+	return o
+}
+
+// Map is a saliency map of width x height cells stored in row-major order
+type Map struct {
+	// Values are the score drops caused by occluding the cells, higher values mark more salient regions
+	Values []float32
+	// Width is the number of map columns
+	Width int
+	// Height is the number of map rows
+	Height int
+}
+
+// Occlusion computes the occlusion saliency map of img. It slides the occluding patch over img,
+// re-runs the inference of every occluded image with score and records how much the score dropped
+// compared to the score of the unoccluded image. If opts is nil the default options are used.
+// It runs one inference per map cell, so the patch and stride trade the map resolution for speed.
+// It returns error if any of the inferences fails.
+//
+// The inference is usually run on a device as follows:
+//
+//	score := func(img image.Image) (float32, error) {
+//		res, err := ncs.Infer(graph, queue, prepare(img), struct{}{})
+//		if err != nil {
+//			return 0, err
+//		}
+//		defer res.Release()
+//		...
+//		return scores[class], nil
+//	}
+func Occlusion(img image.Image, score ScoreFunc, opts *Opts) (*Map, error) {
+	b := img.Bounds()
+	if b.Empty() {
+		return nil, fmt.Errorf("Empty image")
+	}
+
+	o := defaults(opts, b)
+
+	base, err := score(img)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Map{
+		Width:  cells(b.Dx(), o.Patch, o.Stride),
+		Height: cells(b.Dy(), o.Patch, o.Stride),
+	}
+	m.Values = make([]float32, m.Width*m.Height)
+
+	occluded := image.NewRGBA(b)
+	fill := image.NewUniform(o.Fill)
+
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			min := b.Min.Add(image.Pt(x*o.Stride, y*o.Stride))
+			patch := image.Rectangle{Min: min, Max: min.Add(image.Pt(o.Patch, o.Patch))}.Intersect(b)
+
+			draw.Draw(occluded, b, img, b.Min, draw.Src)
+			draw.Draw(occluded, patch, fill, image.Point{}, draw.Src)
+
+			s, err := score(occluded)
+			if err != nil {
+				return nil, err
+			}
+
+			m.Values[y*m.Width+x] = base - s
+		}
+	}
+
+	return m, nil
+}
+
+// cells returns the number of patch positions with the given stride along side of the given size.
+// If the last patch does not reach the end of the side, one more patch clipped by the image is added
+// as long as it starts inside the image, which is not the case if stride is larger than patch.
+func cells(size, patch, stride int) int {
+	if size <= patch {
+		return 1
+	}
+
+	n := (size-patch)/stride + 1
+	if (n-1)*stride+patch < size && n*stride < size {
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,157 @@
+package saliency
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"reflect"
+	"testing"
+)
+
+func TestCells(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		patch  int
+		stride int
+		want   int
+	}{
+		{name: "SizeEqualPatch", size: 8, patch: 8, stride: 4, want: 1},
+		{name: "SizeSmallerThanPatch", size: 5, patch: 8, stride: 8, want: 1},
+		{name: "Divisible", size: 8, patch: 2, stride: 2, want: 4},
+		{name: "Overlapping", size: 8, patch: 4, stride: 2, want: 3},
+		{name: "NonDivisible", size: 10, patch: 4, stride: 4, want: 3},
+		{name: "NonDivisibleOverlapping", size: 9, patch: 4, stride: 2, want: 4},
+		{name: "StrideLargerThanPatch", size: 10, patch: 2, stride: 5, want: 2},
+		{name: "StrideLargerThanPatchClipped", size: 11, patch: 2, stride: 5, want: 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			n := cells(tc.size, tc.patch, tc.stride)
+			if n != tc.want {
+				t.Fatalf("expected %d cells, got: %d", tc.want, n)
+			}
+
+			// every patch must overlap the side
+			if last := (n - 1) * tc.stride; last >= tc.size {
+				t.Errorf("expected last patch to start inside %d, got: %d", tc.size, last)
+			}
+		})
+	}
+}
+
+// fill returns img of the given bounds filled with c
+func fill(b image.Rectangle, c color.Color) *image.RGBA {
+	img := image.NewRGBA(b)
+	draw.Draw(img, b, image.NewUniform(c), image.Point{}, draw.Src)
+
+	return img
+}
+
+func TestOcclusion(t *testing.T) {
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	black := color.RGBA{A: 0xff}
+
+	tests := []struct {
+		name    string
+		bounds  image.Rectangle
+		opts    *Opts
+		width   int
+		height  int
+		salient int
+		patches []int
+	}{
+		{name: "Grid", bounds: image.Rect(0, 0, 8, 8), opts: &Opts{Patch: 4, Fill: black}, width: 2, height: 2, salient: 1,
+			patches: []int{16, 16, 16, 16}},
+		{name: "NonDivisible", bounds: image.Rect(0, 0, 10, 6), opts: &Opts{Patch: 4, Fill: black}, width: 3, height: 2, salient: 1,
+			patches: []int{16, 16, 8, 8, 8, 4}},
+		{name: "Offset", bounds: image.Rect(20, 10, 28, 18), opts: &Opts{Patch: 4, Fill: black}, width: 2, height: 2, salient: 1,
+			patches: []int{16, 16, 16, 16}},
+		{name: "Defaults", bounds: image.Rect(0, 0, 16, 8), width: 16, height: 8, salient: 4,
+			patches: func() []int {
+				p := make([]int, 16*8)
+				for i := range p {
+					p[i] = 1
+				}
+				return p
+			}()},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			img := fill(tc.bounds, white)
+			// the model only responds to the pixel at (4, 0) relative to the image
+			salient := tc.bounds.Min.Add(image.Pt(4, 0))
+
+			var patches []int
+			score := func(img image.Image) (float32, error) {
+				occluded := 0
+				b := img.Bounds()
+				for y := b.Min.Y; y < b.Max.Y; y++ {
+					for x := b.Min.X; x < b.Max.X; x++ {
+						if r, _, _, _ := img.At(x, y).RGBA(); r != 0xffff {
+							occluded++
+						}
+					}
+				}
+				patches = append(patches, occluded)
+
+				r, _, _, _ := img.At(salient.X, salient.Y).RGBA()
+				return float32(r) / 0xffff, nil
+			}
+
+			m, err := Occlusion(img, score, tc.opts)
+			if err != nil {
+				t.Fatalf("failed to compute saliency: %v", err)
+			}
+
+			if m.Width != tc.width || m.Height != tc.height || len(m.Values) != tc.width*tc.height {
+				t.Fatalf("expected %dx%d map, got: %dx%d with %d values", tc.width, tc.height, m.Width, m.Height, len(m.Values))
+			}
+
+			for i, v := range m.Values {
+				want := float32(0)
+				if i == tc.salient {
+					want = 1
+					if tc.opts == nil {
+						// the default fill is mid gray
+						want = 1 - float32(0x8080)/0xffff
+					}
+				}
+				if v != want {
+					t.Errorf("cell %d: expected %v, got: %v", i, want, v)
+				}
+			}
+
+			// the first inference runs on the unoccluded image, the others occlude exactly one patch
+			if !reflect.DeepEqual(patches, append([]int{0}, tc.patches...)) {
+				t.Errorf("expected occluded patches %v, got: %v", tc.patches, patches[1:])
+			}
+		})
+	}
+}
+
+func TestOcclusionErrors(t *testing.T) {
+	errScore := errors.New("inference failed")
+
+	if _, err := Occlusion(image.NewRGBA(image.Rect(0, 0, 0, 8)), nil, nil); err == nil {
+		t.Errorf("expected error for empty image, got nil")
+	}
+
+	for _, fail := range []int{1, 3} {
+		calls := 0
+		score := func(img image.Image) (float32, error) {
+			calls++
+			if calls == fail {
+				return 0, errScore
+			}
+			return 1, nil
+		}
+
+		if _, err := Occlusion(image.NewRGBA(image.Rect(0, 0, 8, 8)), score, &Opts{Patch: 4}); !errors.Is(err, errScore) {
+			t.Errorf("inference %d: expected error: %v, got: %v", fail, errScore, err)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package ncs
+
+import (
+	"context"
+	"sync"
+)
+
+// sessionOpts stores the settings of a session
+type sessionOpts struct {
+	index     *int
+	graphName string
+	inOpts    *FifoOpts
+	outOpts   *FifoOpts
+	opts      []Opt
+}
+
+// SessionOpt configures session at construction
+type SessionOpt func(*sessionOpts)
+
+// WithDeviceIndex sets the index of the device the session runs on. It overrides NCS_DEVICE_INDEX
+// and NCS_DEVICE_SERIAL environment variables. See NewDeviceFromEnv for the defaults.
+func WithDeviceIndex(index int) SessionOpt {
+	return func(o *sessionOpts) {
+		o.index = &index
+	}
+}
+
+// WithGraphName sets the name of the session graph. It defaults to "SessionGraph".
+func WithGraphName(name string) SessionOpt {
+	return func(o *sessionOpts) {
+		o.graphName = name
+	}
+}
+
+// WithFifoOpts sets the options of the inbound and outbound FIFOs allocated along with the session graph.
+// If either of them is nil, the FIFO is allocated with the same options as in Graph.AllocateWithFifosDefault,
+// i.e. its depth is read from NCS_FIFO_DEPTH environment variable.
+func WithFifoOpts(inOpts, outOpts *FifoOpts) SessionOpt {
+	return func(o *sessionOpts) {
+		o.inOpts, o.outOpts = inOpts, outOpts
+	}
+}
+
+// WithHandleOpts sets the options the session device and graph handles are created with,
+// e.g. WithTimeout or WithLogger. The FIFOs inherit them from the graph. Each handle is only created
+// with the options it supports, e.g. WithAllocator only applies to the graph and WithSerial to the device.
+func WithHandleOpts(opts ...Opt) SessionOpt {
+	return func(o *sessionOpts) {
+		o.opts = append(o.opts, opts...)
+	}
+}
+
+// Session owns the device, graph and FIFO queue handles required to run inferences
+// of a single graph and manages their lifecycle.
+type Session struct {
+	// Device is the session device
+	Device *Device
+	// Graph is the session graph
+	Graph *Graph
+	// Queue is the FIFO queue allocated along with the graph
+	Queue *FifoQueue
+	// mu guards closed
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewSession opens the device, allocates graphData on it along with its FIFO queue and returns the session
+// which owns them. The session can be configured via opts. It must be closed via Close once it's no longer used.
+// The device is created via NewDeviceFromEnv, so unless opts say otherwise, the session honors NCS_DEVICE_INDEX,
+// NCS_DEVICE_SERIAL, NCS_FIFO_DEPTH and NCS_LOG_LEVEL environment variables.
+// If any of the steps fails, the handles which have already been created are torn down before the error is returned.
+func NewSession(graphData []byte, opts ...SessionOpt) (*Session, error) {
+	return NewSessionContext(context.Background(), graphData, opts...)
+}
+
+// NewSessionContext opens the device and allocates graphData on it along with its FIFO queue.
+// It returns ctx error if ctx is done before the session is created. See NewSession for more details.
+func NewSessionContext(ctx context.Context, graphData []byte, opts ...SessionOpt) (*Session, error) {
+	o := sessionOpts{
+		graphName: "SessionGraph",
+	}
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	s := &Session{}
+
+	var err error
+	defer func() {
+		if err != nil {
+			s.teardown()
+		}
+	}()
+
+	devOpts := supportedOpts("device", o.opts)
+	if o.index != nil {
+		devOpts = append(devOpts, WithIndex(*o.index))
+	}
+
+	if s.Device, err = NewDeviceFromEnv(devOpts...); err != nil {
+		return nil, err
+	}
+
+	if err = s.Device.OpenContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if s.Graph, err = NewGraph(o.graphName, supportedOpts("graph", o.opts)...); err != nil {
+		return nil, err
+	}
+
+	var depth int
+	if depth, err = envFifoDepth(); err != nil {
+		return nil, err
+	}
+
+	inOpts, outOpts := o.inOpts, o.outOpts
+	if inOpts == nil {
+		inOpts = &FifoOpts{FifoHostWO, FifoFP32, depth}
+	}
+	if outOpts == nil {
+		outOpts = &FifoOpts{FifoHostRO, FifoFP32, depth}
+	}
+
+	if s.Queue, err = s.Graph.AllocateWithFifosOptsContext(ctx, s.Device, graphData, inOpts, outOpts); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Infer queues data for inference and waits for the output tensor. See Graph.Infer for more details.
+func (s *Session) Infer(data []byte) (*Tensor, error) {
+	return s.InferContext(context.Background(), data)
+}
+
+// InferContext queues data for inference and waits for the output tensor.
+// It returns ctx error if ctx is done before the output tensor is read. See Graph.Infer for more details.
+func (s *Session) InferContext(ctx context.Context, data []byte) (*Tensor, error) {
+	if s == nil {
+		return nil, invalidHandle("Infer", "graph")
+	}
+
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+
+	if closed {
+		return nil, ErrClosed
+	}
+
+	return s.Graph.InferContext(ctx, s.Queue, data)
+}
+
+// Close tears down the session handles in the order required by NCSDK: it drains the outbound FIFO,
+// destroys both FIFOs, then the graph and finally closes and destroys the device.
+// It carries on if tearing down any handle fails and returns the first error it encountered.
+// It returns ErrClosed if the session has already been closed. Close implements io.Closer interface.
+// Close must not be called while there are inferences in progress.
+func (s *Session) Close() error {
+	if s == nil {
+		return invalidHandle("Close", "device")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	s.closed = true
+
+	return s.teardown()
+}
+
+// teardown tears down the session handles which have been created and returns the first error it encountered
+func (s *Session) teardown() error {
+	var first error
+	fail := func(err error) {
+		if first == nil && err != nil && err != ErrClosed {
+			first = err
+		}
+	}
+
+	if s.Queue != nil {
+		fail(s.Queue.Out.drain(context.Background()))
+		fail(s.Queue.Close())
+	}
+
+	if s.Graph != nil {
+		fail(s.Graph.Destroy())
+	}
+
+	if s.Device != nil {
+		fail(s.Device.Close())
+		fail(s.Device.Destroy())
+	}
+
+	return first
+}
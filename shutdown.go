@@ -0,0 +1,68 @@
+package ncs
+
+import (
+	"context"
+	"errors"
+)
+
+// Shutdown tears down all the device, graph and FIFO handles which have not been destroyed yet
+// in the order required by NCSDK: it first drains the elements left in the outbound FIFOs,
+// then destroys all the FIFOs, then the graphs and finally the devices.
+// Destroying a device also closes it if it is still open.
+//
+// Shutdown stops and returns ctx error if ctx is done before the teardown is finished.
+// The handles which have not been destroyed yet are left intact, so Shutdown can be called again.
+// If destroying any handle fails, Shutdown carries on and returns the first error it encountered.
+//
+// Shutdown must not be called while any of the handles are still in use.
+func Shutdown(ctx context.Context) error {
+	devices, graphs, fifos := liveHandles()
+
+	var first error
+	fail := func(err error) {
+		if first == nil && err != nil && err != ErrClosed && err != ErrStaleHandle {
+			first = err
+		}
+	}
+
+	for _, f := range fifos {
+		if f.typ != FifoHostRO {
+			continue
+		}
+
+		if err := f.drain(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			// FIFOs which have not been allocated have nothing to drain
+			var serr *StateError
+			if !errors.As(err, &serr) {
+				fail(err)
+			}
+		}
+	}
+
+	for _, f := range fifos {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fail(f.Destroy())
+	}
+
+	for _, g := range graphs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fail(g.Destroy())
+	}
+
+	for _, d := range devices {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fail(d.Destroy())
+	}
+
+	return first
+}
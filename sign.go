@@ -0,0 +1,58 @@
+package ncs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+)
+
+// ErrInvalidSignature is returned when the graph blob signature is missing or does not match the graph data.
+// It wraps ErrUnsupportedGraphFile.
+var ErrInvalidSignature = fmt.Errorf("Invalid graph signature: %w", ErrUnsupportedGraphFile)
+
+// SignGraph returns detached signature of graph blob data signed by key, which must be
+// either ed25519.PrivateKey or *ecdsa.PrivateKey. Ed25519 signs the data directly,
+// ECDSA signs its SHA-256 digest and returns ASN.1 encoded signature.
+// It returns error if the key type is not supported or if signing fails.
+func SignGraph(data []byte, key crypto.Signer) ([]byte, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, data), nil
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(data)
+		return ecdsa.SignASN1(rand.Reader, k, digest[:])
+	default:
+		return nil, fmt.Errorf("Unsupported graph signing key type %T: %w", key, ErrInvalidParameters)
+	}
+}
+
+// VerifyGraph verifies detached signature sig of graph blob data created by SignGraph with the private key of pub,
+// which must be either ed25519.PublicKey or *ecdsa.PublicKey. Graphs should be verified before they are allocated
+// to refuse unsigned or tampered models.
+// It returns ErrInvalidSignature if the signature is missing or invalid, or error if the key type is not supported.
+func VerifyGraph(data, sig []byte, pub crypto.PublicKey) error {
+	if len(sig) == 0 {
+		return ErrInvalidSignature
+	}
+
+	var ok bool
+
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(k, data, sig)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		ok = ecdsa.VerifyASN1(k, digest[:], sig)
+	default:
+		return fmt.Errorf("Unsupported graph verification key type %T: %w", pub, ErrInvalidParameters)
+	}
+
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
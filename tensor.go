@@ -0,0 +1,303 @@
+package ncs
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/milosgajdos/ncs/float16"
+)
+
+// NewTensor reads all the data from r and returns it in a new Tensor along with metaData.
+// It returns error if it fails to read the data.
+func NewTensor(r io.Reader, metaData interface{}) (*Tensor, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tensor{
+		Data:     data,
+		MetaData: metaData,
+	}, nil
+}
+
+// Read reads the tensor data which has not been read yet into p.
+// It implements io.Reader interface and returns io.EOF once all the data has been read.
+func (t *Tensor) Read(p []byte) (int, error) {
+	if t.off >= len(t.Data) {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+
+	n := copy(p, t.Data[t.off:])
+	t.off += n
+
+	return n, nil
+}
+
+// WriteTo writes the tensor data which has not been read yet to w.
+// It implements io.WriterTo interface, so io.Copy writes the data without intermediate copies.
+func (t *Tensor) WriteTo(w io.Writer) (int64, error) {
+	if t.off >= len(t.Data) {
+		return 0, nil
+	}
+
+	data := t.Data[t.off:]
+	n, err := w.Write(data)
+	t.off += n
+
+	if err == nil && n < len(data) {
+		err = io.ErrShortWrite
+	}
+
+	return int64(n), err
+}
+
+// DumpOpts configures Tensor Dump
+type DumpOpts struct {
+	// Desc describes the tensor shape and data type. If nil, the data is dumped as FP32 values of unknown shape.
+	Desc *TensorDesc
+	// Values is the number of leading values to print. Zero value prints 10 values, negative value prints none.
+	Values int
+	// CSV dumps all the tensor values as index,value CSV records instead of the summary
+	CSV bool
+}
+
+// Dump writes human readable summary of the tensor data to w: its shape, data type, number of elements,
+// min, max and mean values, number of zero values and the first few values.
+// This is useful when debugging results which contain all zeros or which have been decoded with the wrong layout.
+// If opts is nil the default options are used. It returns error if it fails to write to w.
+func (t *Tensor) Dump(w io.Writer, opts *DumpOpts) error {
+	if opts == nil {
+		opts = &DumpOpts{}
+	}
+
+	dataType := FifoFP32
+	if opts.Desc != nil {
+		dataType = opts.Desc.DataType
+	}
+
+	vals, err := decodeValues(t.Data, dataType)
+	if err != nil {
+		return err
+	}
+
+	if opts.CSV {
+		if _, err := fmt.Fprintln(w, "index,value"); err != nil {
+			return err
+		}
+
+		for i, v := range vals {
+			if _, err := fmt.Fprintf(w, "%d,%g\n", i, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	shape := "unknown"
+	if td := opts.Desc; td != nil {
+		shape = fmt.Sprintf("%dx%dx%dx%d (NxCxHxW)", td.BatchSize, td.Channels, td.Height, td.Width)
+	}
+
+	var min, max, sum float64
+	var zeros int
+	for i, v := range vals {
+		f := float64(v)
+		if i == 0 || f < min {
+			min = f
+		}
+		if i == 0 || f > max {
+			max = f
+		}
+		if f == 0 {
+			zeros++
+		}
+		sum += f
+	}
+
+	var mean float64
+	if len(vals) > 0 {
+		mean = sum / float64(len(vals))
+	}
+
+	n := opts.Values
+	if n == 0 {
+		n = 10
+	}
+	if n < 0 {
+		n = 0
+	}
+	if n > len(vals) {
+		n = len(vals)
+	}
+
+	_, err = fmt.Fprintf(w, "shape: %s\ndtype: %s\nelements: %d\nmin: %g\nmax: %g\nmean: %g\nzeros: %d\nvalues: %v\n",
+		shape, dataType, len(vals), min, max, mean, zeros, vals[:n])
+
+	return err
+}
+
+// Values decodes the tensor data of data type dt into float32 values.
+// It returns error if dt is not a known data type.
+func (t *Tensor) Values(dt FifoDataType) ([]float32, error) {
+	return decodeValues(t.Data, dt)
+}
+
+// Mismatch is a value which differs between two compared tensors.
+// Its NaN and infinite values are encoded to JSON as strings "NaN", "+Inf" and "-Inf".
+type Mismatch struct {
+	// Index is the index of the value
+	Index int `json:"index"`
+	// Got is the value of the compared tensor
+	Got float32 `json:"got"`
+	// Want is the value of the reference tensor
+	Want float32 `json:"want"`
+	// AbsErr is the absolute difference between the values.
+	// It is +Inf if exactly one of the values is NaN or if the values are different infinities
+	// or an infinity and a finite number.
+	AbsErr float64 `json:"abs_err"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (m Mismatch) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Index  int             `json:"index"`
+		Got    json.RawMessage `json:"got"`
+		Want   json.RawMessage `json:"want"`
+		AbsErr json.RawMessage `json:"abs_err"`
+	}{
+		Index:  m.Index,
+		Got:    marshalFloat(float64(m.Got), 32),
+		Want:   marshalFloat(float64(m.Want), 32),
+		AbsErr: marshalFloat(m.AbsErr, 64),
+	})
+}
+
+// marshalFloat encodes v of the given bit size to JSON. NaN and infinities, which JSON numbers
+// can not represent, are encoded as strings "NaN", "+Inf" and "-Inf".
+func marshalFloat(v float64, bitSize int) json.RawMessage {
+	switch {
+	case math.IsNaN(v):
+		return json.RawMessage(`"NaN"`)
+	case math.IsInf(v, 1):
+		return json.RawMessage(`"+Inf"`)
+	case math.IsInf(v, -1):
+		return json.RawMessage(`"-Inf"`)
+	}
+
+	// finite numbers are always encoded successfully
+	var data []byte
+	if bitSize == 32 {
+		data, _ = json.Marshal(float32(v))
+	} else {
+		data, _ = json.Marshal(v)
+	}
+
+	return data
+}
+
+// TensorDiff summarizes the differences between two tensors
+type TensorDiff struct {
+	// Count is the number of compared values
+	Count int `json:"count"`
+	// MaxAbsErr is the maximum absolute difference between the values which are not NonFinite mismatches
+	MaxAbsErr float64 `json:"max_abs_err"`
+	// MeanAbsErr is the mean absolute difference between the values which are not NonFinite mismatches
+	MeanAbsErr float64 `json:"mean_abs_err"`
+	// NonFinite is the number of values which differ because exactly one of them is NaN or because
+	// they are different infinities or an infinity and a finite number. Their absolute difference is +Inf.
+	NonFinite int `json:"non_finite"`
+	// Top contains the values which differ the most, ordered by decreasing absolute difference
+	Top []Mismatch `json:"top"`
+}
+
+// Compare compares the tensor values got with the reference values want, e.g. the output of mvNCCheck
+// or of the reference model, and returns the summary of their differences including the k values which differ the most.
+// Two NaN values or two infinities of the same sign are considered equal.
+// It returns error if the number of values differs.
+func Compare(got, want []float32, k int) (*TensorDiff, error) {
+	if len(got) != len(want) {
+		return nil, fmt.Errorf("Tensor size mismatch: got %d values, want %d: %w", len(got), len(want), ErrInvalidParameters)
+	}
+
+	diff := &TensorDiff{Count: len(got)}
+	mismatches := make([]Mismatch, 0, len(got))
+
+	var sum float64
+	for i := range got {
+		g, w := float64(got[i]), float64(want[i])
+
+		var absErr float64
+		switch {
+		case math.IsNaN(g) || math.IsNaN(w):
+			if !math.IsNaN(g) || !math.IsNaN(w) {
+				absErr = math.Inf(1)
+			}
+		case math.IsInf(g, 0) || math.IsInf(w, 0):
+			if g != w {
+				absErr = math.Inf(1)
+			}
+		default:
+			absErr = math.Abs(g - w)
+		}
+
+		if math.IsInf(absErr, 1) {
+			diff.NonFinite++
+		} else {
+			if absErr > diff.MaxAbsErr {
+				diff.MaxAbsErr = absErr
+			}
+			sum += absErr
+		}
+
+		if absErr > 0 {
+			mismatches = append(mismatches, Mismatch{Index: i, Got: got[i], Want: want[i], AbsErr: absErr})
+		}
+	}
+
+	if n := len(got) - diff.NonFinite; n > 0 {
+		diff.MeanAbsErr = sum / float64(n)
+	}
+
+	sort.SliceStable(mismatches, func(i, j int) bool {
+		return mismatches[i].AbsErr > mismatches[j].AbsErr
+	})
+
+	if k < 0 {
+		k = 0
+	}
+	if k > len(mismatches) {
+		k = len(mismatches)
+	}
+	diff.Top = mismatches[:k]
+
+	return diff, nil
+}
+
+// decodeValues decodes raw tensor data of data type dt into float32 values
+func decodeValues(data []byte, dt FifoDataType) ([]float32, error) {
+	switch dt {
+	case FifoFP32:
+		vals := make([]float32, len(data)/4)
+		for i := range vals {
+			vals[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+		}
+		return vals, nil
+	case FifoFP16:
+		vals := make([]float32, len(data)/2)
+		for i := range vals {
+			vals[i] = float16.FromBits(binary.LittleEndian.Uint16(data[2*i:])).Float32()
+		}
+		return vals, nil
+	default:
+		return nil, fmt.Errorf("Unknown tensor data type %d: %w", dt, ErrInvalidParameters)
+	}
+}
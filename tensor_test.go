@@ -0,0 +1,105 @@
+package ncs
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(1))
+
+	tests := []struct {
+		name      string
+		got       []float32
+		want      []float32
+		k         int
+		maxAbsErr float64
+		meanAbs   float64
+		nonFinite int
+		top       []int
+	}{
+		{name: "Empty", k: 3, top: []int{}},
+		{name: "Equal", got: []float32{1, 2, 3}, want: []float32{1, 2, 3}, k: 3, top: []int{}},
+		{name: "Diff", got: []float32{1, 2, 3, 4}, want: []float32{1, 2.5, 1, 4}, k: 3, maxAbsErr: 2, meanAbs: 0.625, top: []int{2, 1}},
+		{name: "TopK", got: []float32{1, 2, 3, 4}, want: []float32{1, 2.5, 1, 4}, k: 1, maxAbsErr: 2, meanAbs: 0.625, top: []int{2}},
+		{name: "NegativeK", got: []float32{1, 2}, want: []float32{2, 2}, k: -1, maxAbsErr: 1, meanAbs: 0.5, top: []int{}},
+		{name: "NaNEqual", got: []float32{nan, 1}, want: []float32{nan, 1}, k: 3, top: []int{}},
+		{name: "NaNGot", got: []float32{1, nan, 2}, want: []float32{2, 1, 2}, k: 3, maxAbsErr: 1, meanAbs: 0.5, nonFinite: 1, top: []int{1, 0}},
+		{name: "NaNWant", got: []float32{1, 1}, want: []float32{1, nan}, k: 3, nonFinite: 1, top: []int{1}},
+		{name: "InfEqual", got: []float32{inf, -inf}, want: []float32{inf, -inf}, k: 3, top: []int{}},
+		{name: "InfSign", got: []float32{inf, 1}, want: []float32{-inf, 1}, k: 3, nonFinite: 1, top: []int{0}},
+		{name: "InfFinite", got: []float32{1, 3}, want: []float32{inf, 1}, k: 3, maxAbsErr: 2, meanAbs: 2, nonFinite: 1, top: []int{0, 1}},
+		{name: "AllNonFinite", got: []float32{nan, inf}, want: []float32{1, 1}, k: 3, nonFinite: 2, top: []int{0, 1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, err := Compare(tc.got, tc.want, tc.k)
+			if err != nil {
+				t.Fatalf("failed to compare tensors: %v", err)
+			}
+
+			if diff.Count != len(tc.got) {
+				t.Errorf("expected count %d, got: %d", len(tc.got), diff.Count)
+			}
+
+			if diff.MaxAbsErr != tc.maxAbsErr {
+				t.Errorf("expected max abs err %v, got: %v", tc.maxAbsErr, diff.MaxAbsErr)
+			}
+
+			if diff.MeanAbsErr != tc.meanAbs {
+				t.Errorf("expected mean abs err %v, got: %v", tc.meanAbs, diff.MeanAbsErr)
+			}
+
+			if diff.NonFinite != tc.nonFinite {
+				t.Errorf("expected %d non-finite mismatches, got: %d", tc.nonFinite, diff.NonFinite)
+			}
+
+			top := make([]int, len(diff.Top))
+			for i, m := range diff.Top {
+				top[i] = m.Index
+				if i < tc.nonFinite && !math.IsInf(m.AbsErr, 1) {
+					t.Errorf("mismatch %d: expected +Inf abs err, got: %v", m.Index, m.AbsErr)
+				}
+			}
+
+			if !reflect.DeepEqual(top, tc.top) {
+				t.Errorf("expected top mismatches %v, got: %v", tc.top, top)
+			}
+		})
+	}
+}
+
+func TestCompareSizeMismatch(t *testing.T) {
+	if _, err := Compare([]float32{1, 2}, []float32{1}, 1); !errors.Is(err, ErrInvalidParameters) {
+		t.Errorf("expected error: %v, got: %v", ErrInvalidParameters, err)
+	}
+}
+
+func TestTensorDiffJSON(t *testing.T) {
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(1))
+
+	diff, err := Compare([]float32{nan, inf, 1.5, 2}, []float32{1, -inf, 1, 2}, 10)
+	if err != nil {
+		t.Fatalf("failed to compare tensors: %v", err)
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatalf("failed to encode diff: %v", err)
+	}
+
+	want := `{"count":4,"max_abs_err":0.5,"mean_abs_err":0.25,"non_finite":2,"top":[` +
+		`{"index":0,"got":"NaN","want":1,"abs_err":"+Inf"},` +
+		`{"index":1,"got":"+Inf","want":"-Inf","abs_err":"+Inf"},` +
+		`{"index":2,"got":1.5,"want":1,"abs_err":0.5}]}`
+
+	if string(data) != want {
+		t.Errorf("expected %s, got: %s", want, data)
+	}
+}
@@ -0,0 +1,904 @@
+/*
+ * Fake NCSDK2 library used by the test suite.
+ *
+ * It implements the NCSDK2 API declared in mvnc.h without any hardware:
+ *
+ * - MVNC_FAKE_DEVICE_COUNT devices are attached (1 by default, at most 16)
+ * - every graph is an identity network with a single 1x1x2x2 input and output
+ * - the elements queued for inference are copied from the input to the output FIFO
+ * - reading an empty FIFO or writing a full one waits up to a second and then fails with NC_TIMEOUT
+ * - MVNC_FAKE_READ_DELAY_MS delays every FIFO read to simulate a slow device
+ * - NC_RO_DEVICE_MAX_EXECUTORS is not supported, as with older firmware
+ *
+ * All the options are reported with the same layout and sizes as by libmvnc.
+ */
+#include <stdlib.h>
+#include <string.h>
+#include <stdio.h>
+#include <unistd.h>
+#include <time.h>
+#include <pthread.h>
+
+#include "mvnc.h"
+
+#define FAKE_MAX_DEVICES 16
+#define FAKE_NAME_SIZE 28
+#define FAKE_THERMAL_SIZE 100
+#define FAKE_MAX_GRAPHS 10
+#define FAKE_MAX_FIFOS 20
+#define FAKE_MAX_ELEMS 64
+#define FAKE_LAYERS 3
+#define FAKE_WAIT_SEC 1
+
+/* lock guards all the fake device, graph and FIFO state */
+static pthread_mutex_t lock = PTHREAD_MUTEX_INITIALIZER;
+
+/* changed is signalled whenever an element is appended to or removed from any FIFO */
+static pthread_cond_t changed = PTHREAD_COND_INITIALIZER;
+
+static int log_level = 1;
+
+/* graphs and fifos count the graphs and FIFOs allocated per device index, so they outlive the device handles */
+static int graphs[FAKE_MAX_DEVICES];
+static int fifos[FAKE_MAX_DEVICES];
+
+struct fake_device {
+    int index;
+    int state;
+};
+
+struct fake_graph {
+    char name[FAKE_NAME_SIZE];
+    int state;
+    /* device is the index of the device the graph is allocated for, -1 if it's not allocated */
+    int device;
+    struct ncTensorDescriptor_t desc;
+};
+
+struct fake_elem {
+    void *data;
+    void *param;
+};
+
+struct fake_fifo {
+    char name[FAKE_NAME_SIZE];
+    ncFifoType_t type;
+    ncFifoDataType_t data_type;
+    int consumers;
+    int state;
+    unsigned int capacity;
+    /* device is the index of the device the FIFO is allocated for, -1 if it's not allocated */
+    int device;
+    struct ncTensorDescriptor_t graph_desc;
+    struct ncTensorDescriptor_t host_desc;
+    struct fake_elem elems[FAKE_MAX_ELEMS];
+    unsigned int head;
+    unsigned int count;
+};
+
+enum { DEVICE_CREATED, DEVICE_OPENED, DEVICE_CLOSED };
+enum { GRAPH_CREATED, GRAPH_ALLOCATED };
+enum { FIFO_CREATED, FIFO_ALLOCATED };
+
+/* reply copies size bytes of value into data or reports the required length if data is too short */
+static ncStatus_t reply(const void *value, unsigned int size, void *data, unsigned int *dataLength) {
+    if (dataLength == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    if (data == NULL || *dataLength < size) {
+        *dataLength = size;
+        return NC_INVALID_DATA_LENGTH;
+    }
+
+    memcpy(data, value, size);
+    *dataLength = size;
+
+    return NC_OK;
+}
+
+static ncStatus_t reply_uint(unsigned int value, void *data, unsigned int *dataLength) {
+    return reply(&value, sizeof(value), data, dataLength);
+}
+
+static ncStatus_t reply_string(const char *value, void *data, unsigned int *dataLength) {
+    return reply(value, strlen(value) + 1, data, dataLength);
+}
+
+/* fake_wait waits for any FIFO to change until deadline, it must be called with lock held.
+ * Like libmvnc it blocks the calls which can't proceed, but not forever so the tests can't hang.
+ * It returns non-zero if the deadline has passed. */
+static int fake_wait(struct timespec *deadline) {
+    if (deadline->tv_sec == 0) {
+        clock_gettime(CLOCK_REALTIME, deadline);
+        deadline->tv_sec += FAKE_WAIT_SEC;
+    }
+
+    return pthread_cond_timedwait(&changed, &lock, deadline);
+}
+
+static int env_int(const char *name, int def) {
+    const char *val = getenv(name);
+    return val ? atoi(val) : def;
+}
+
+/* fake_desc returns the descriptor of the 1x1x2x2 FP32 identity network tensors */
+static struct ncTensorDescriptor_t fake_desc(void) {
+    struct ncTensorDescriptor_t desc = {1, 1, 2, 2, 4 * sizeof(float), sizeof(float), sizeof(float), 2 * sizeof(float), NC_FIFO_FP32};
+    return desc;
+}
+
+/* elem_size returns the size of the FIFO elements in bytes */
+static unsigned int elem_size(struct fake_fifo *f) {
+    unsigned int n = f->host_desc.n * f->host_desc.c * f->host_desc.w * f->host_desc.h;
+    return f->data_type == NC_FIFO_FP16 ? n * 2 : n * 4;
+}
+
+ncStatus_t ncGlobalSetOption(int option, const void *data, unsigned int dataLength) {
+    if (data == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    switch (option) {
+    case 0:
+        if (dataLength != sizeof(int)) {
+            return NC_INVALID_DATA_LENGTH;
+        }
+        pthread_mutex_lock(&lock);
+        log_level = *(const int *)data;
+        pthread_mutex_unlock(&lock);
+        return NC_OK;
+    case 1:
+        return NC_UNAUTHORIZED;
+    default:
+        return NC_INVALID_PARAMETERS;
+    }
+}
+
+ncStatus_t ncGlobalGetOption(int option, void *data, unsigned int *dataLength) {
+    unsigned int version[4] = {2, 10, 1, 0};
+    ncStatus_t s;
+
+    switch (option) {
+    case 0:
+        pthread_mutex_lock(&lock);
+        s = reply_uint(log_level, data, dataLength);
+        pthread_mutex_unlock(&lock);
+        return s;
+    case 1:
+        return reply(version, sizeof(version), data, dataLength);
+    default:
+        return NC_INVALID_PARAMETERS;
+    }
+}
+
+ncStatus_t ncDeviceCreate(int index, struct ncDeviceHandle_t **deviceHandle) {
+    struct fake_device *d;
+
+    if (deviceHandle == NULL || index < 0) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    if (index >= env_int("MVNC_FAKE_DEVICE_COUNT", 1) || index >= FAKE_MAX_DEVICES) {
+        return NC_DEVICE_NOT_FOUND;
+    }
+
+    d = calloc(1, sizeof(*d));
+    d->index = index;
+    *deviceHandle = calloc(1, sizeof(**deviceHandle));
+    (*deviceHandle)->private_data = d;
+
+    return NC_OK;
+}
+
+ncStatus_t ncDeviceOpen(struct ncDeviceHandle_t *deviceHandle) {
+    struct fake_device *d;
+
+    if (deviceHandle == NULL || deviceHandle->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    d = deviceHandle->private_data;
+    pthread_mutex_lock(&lock);
+    d->state = DEVICE_OPENED;
+    pthread_mutex_unlock(&lock);
+
+    return NC_OK;
+}
+
+ncStatus_t ncDeviceGetOption(struct ncDeviceHandle_t *deviceHandle, int option, void *data, unsigned int *dataLength) {
+    float thermal[FAKE_THERMAL_SIZE];
+    unsigned int fw[4] = {2, 10, 1, 0};
+    unsigned int mvtensor[2] = {1, 0};
+    char name[FAKE_NAME_SIZE];
+    struct fake_device *d;
+    ncStatus_t s;
+    int i;
+
+    if (deviceHandle == NULL || deviceHandle->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    d = deviceHandle->private_data;
+    pthread_mutex_lock(&lock);
+
+    switch (option) {
+    case 2000:
+        for (i = 0; i < FAKE_THERMAL_SIZE; i++) {
+            thermal[i] = 40.0f;
+        }
+        s = reply(thermal, sizeof(thermal), data, dataLength);
+        break;
+    case 2001:
+        s = reply_uint(0, data, dataLength);
+        break;
+    case 2002:
+        s = reply_uint(d->state, data, dataLength);
+        break;
+    case 2003:
+        s = reply_uint(graphs[d->index] * 1024 * 1024, data, dataLength);
+        break;
+    case 2004:
+        s = reply_uint(512 * 1024 * 1024, data, dataLength);
+        break;
+    case 2005:
+        s = reply_uint(FAKE_MAX_FIFOS, data, dataLength);
+        break;
+    case 2006:
+        s = reply_uint(fifos[d->index], data, dataLength);
+        break;
+    case 2007:
+        s = reply_uint(FAKE_MAX_GRAPHS, data, dataLength);
+        break;
+    case 2008:
+        s = reply_uint(graphs[d->index], data, dataLength);
+        break;
+    case 2009:
+        s = reply_uint(1, data, dataLength);
+        break;
+    case 2010:
+        s = reply(fw, sizeof(fw), data, dataLength);
+        break;
+    case 2011:
+        s = reply_string("", data, dataLength);
+        break;
+    case 2012:
+        s = reply(mvtensor, sizeof(mvtensor), data, dataLength);
+        break;
+    case 2013:
+        snprintf(name, sizeof(name), "fake-%d", d->index);
+        s = reply_string(name, data, dataLength);
+        break;
+    case 2014:
+        s = NC_UNSUPPORTED_FEATURE;
+        break;
+    case 2015:
+        s = reply_uint(0, data, dataLength);
+        break;
+    default:
+        s = NC_INVALID_PARAMETERS;
+    }
+
+    pthread_mutex_unlock(&lock);
+
+    return s;
+}
+
+ncStatus_t ncDeviceClose(struct ncDeviceHandle_t *deviceHandle) {
+    struct fake_device *d;
+
+    if (deviceHandle == NULL || deviceHandle->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    d = deviceHandle->private_data;
+    pthread_mutex_lock(&lock);
+    d->state = DEVICE_CLOSED;
+    pthread_mutex_unlock(&lock);
+
+    return NC_OK;
+}
+
+ncStatus_t ncDeviceDestroy(struct ncDeviceHandle_t **deviceHandle) {
+    if (deviceHandle == NULL || *deviceHandle == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    free((*deviceHandle)->private_data);
+    free(*deviceHandle);
+    *deviceHandle = NULL;
+
+    return NC_OK;
+}
+
+ncStatus_t ncGraphCreate(const char *name, struct ncGraphHandle_t **graphHandle) {
+    struct fake_graph *g;
+
+    if (name == NULL || graphHandle == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    g = calloc(1, sizeof(*g));
+    strncpy(g->name, name, FAKE_NAME_SIZE - 1);
+    g->device = -1;
+    g->desc = fake_desc();
+    *graphHandle = calloc(1, sizeof(**graphHandle));
+    (*graphHandle)->private_data = g;
+
+    return NC_OK;
+}
+
+ncStatus_t ncGraphAllocate(struct ncDeviceHandle_t *deviceHandle, struct ncGraphHandle_t *graphHandle,
+                           const void *graphBuffer, unsigned int graphBufferLength) {
+    struct fake_device *d;
+    struct fake_graph *g;
+    ncStatus_t s = NC_OK;
+
+    if (deviceHandle == NULL || graphHandle == NULL || deviceHandle->private_data == NULL || graphHandle->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    if (graphBuffer == NULL || graphBufferLength == 0) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    d = deviceHandle->private_data;
+    g = graphHandle->private_data;
+    pthread_mutex_lock(&lock);
+
+    if (d->state != DEVICE_OPENED) {
+        s = NC_INVALID_HANDLE;
+    } else if (g->state != GRAPH_CREATED) {
+        s = NC_UNAUTHORIZED;
+    } else if (graphs[d->index] == FAKE_MAX_GRAPHS) {
+        s = NC_OUT_OF_MEMORY;
+    } else {
+        g->state = GRAPH_ALLOCATED;
+        g->device = d->index;
+        graphs[d->index]++;
+    }
+
+    pthread_mutex_unlock(&lock);
+
+    return s;
+}
+
+/* fifo_new creates new unallocated FIFO handle */
+static struct ncFifoHandle_t *fifo_new(const char *name, ncFifoType_t type) {
+    struct ncFifoHandle_t *h = calloc(1, sizeof(*h));
+    struct fake_fifo *f = calloc(1, sizeof(*f));
+
+    strncpy(f->name, name, FAKE_NAME_SIZE - 1);
+    f->type = type;
+    f->data_type = NC_FIFO_FP32;
+    f->consumers = 1;
+    f->device = -1;
+    h->private_data = f;
+
+    return h;
+}
+
+/* fifo_allocate allocates the FIFO with numElem elements for the device, it must be called with lock held */
+static ncStatus_t fifo_allocate(struct fake_fifo *f, struct fake_device *d, struct ncTensorDescriptor_t *desc, unsigned int numElem) {
+    if (d->state != DEVICE_OPENED) {
+        return NC_INVALID_HANDLE;
+    }
+
+    if (f->state != FIFO_CREATED) {
+        return NC_UNAUTHORIZED;
+    }
+
+    if (numElem == 0 || numElem > FAKE_MAX_ELEMS) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    if (fifos[d->index] == FAKE_MAX_FIFOS) {
+        return NC_OUT_OF_MEMORY;
+    }
+
+    f->graph_desc = *desc;
+    f->host_desc = *desc;
+    f->capacity = numElem;
+    f->device = d->index;
+    f->state = FIFO_ALLOCATED;
+    fifos[d->index]++;
+
+    return NC_OK;
+}
+
+ncStatus_t ncGraphAllocateWithFifosEx(struct ncDeviceHandle_t *deviceHandle, struct ncGraphHandle_t *graphHandle,
+                                      const void *graphBuffer, unsigned int graphBufferLength,
+                                      struct ncFifoHandle_t **inFifoHandle, ncFifoType_t inFifoType,
+                                      int inNumElem, ncFifoDataType_t inDataType,
+                                      struct ncFifoHandle_t **outFifoHandle, ncFifoType_t outFifoType,
+                                      int outNumElem, ncFifoDataType_t outDataType) {
+    struct fake_device *d;
+    struct fake_fifo *in, *out;
+    ncStatus_t s;
+
+    if (inFifoHandle == NULL || outFifoHandle == NULL || inNumElem <= 0 || outNumElem <= 0) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    s = ncGraphAllocate(deviceHandle, graphHandle, graphBuffer, graphBufferLength);
+    if (s != NC_OK) {
+        return s;
+    }
+
+    d = deviceHandle->private_data;
+    *inFifoHandle = fifo_new("input", inFifoType);
+    *outFifoHandle = fifo_new("output", outFifoType);
+    in = (*inFifoHandle)->private_data;
+    out = (*outFifoHandle)->private_data;
+    in->data_type = inDataType;
+    out->data_type = outDataType;
+
+    pthread_mutex_lock(&lock);
+    s = fifo_allocate(in, d, &((struct fake_graph *)graphHandle->private_data)->desc, inNumElem);
+    if (s == NC_OK) {
+        s = fifo_allocate(out, d, &((struct fake_graph *)graphHandle->private_data)->desc, outNumElem);
+    }
+    pthread_mutex_unlock(&lock);
+
+    if (s != NC_OK) {
+        ncFifoDestroy(inFifoHandle);
+        ncFifoDestroy(outFifoHandle);
+    }
+
+    return s;
+}
+
+ncStatus_t ncGraphGetOption(struct ncGraphHandle_t *graphHandle, int option, void *data, unsigned int *dataLength) {
+    float times[FAKE_LAYERS] = {0.5f, 1.5f, 1.0f};
+    unsigned int version[2] = {2, 0};
+    struct fake_graph *g;
+    ncStatus_t s;
+
+    if (graphHandle == NULL || graphHandle->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    g = graphHandle->private_data;
+    pthread_mutex_lock(&lock);
+
+    switch (option) {
+    case 1000:
+        s = reply_uint(g->state, data, dataLength);
+        break;
+    case 1001:
+        s = reply(times, sizeof(times), data, dataLength);
+        break;
+    case 1002:
+    case 1003:
+        s = reply_uint(1, data, dataLength);
+        break;
+    case 1004:
+    case 1005:
+        s = reply(&g->desc, sizeof(g->desc), data, dataLength);
+        break;
+    case 1006:
+        s = reply_string("", data, dataLength);
+        break;
+    case 1007:
+        s = reply_string(g->name, data, dataLength);
+        break;
+    case 1008:
+        s = reply_uint(1, data, dataLength);
+        break;
+    case 1009:
+        s = reply(version, sizeof(version), data, dataLength);
+        break;
+    case 1010:
+        s = reply_uint(1, data, dataLength);
+        break;
+    case 1011:
+        s = reply_uint(sizeof(times), data, dataLength);
+        break;
+    default:
+        s = NC_INVALID_PARAMETERS;
+    }
+
+    pthread_mutex_unlock(&lock);
+
+    return s;
+}
+
+/* fifo_push appends a copy of size bytes of data to the FIFO, it must be called with lock held */
+static ncStatus_t fifo_push(struct fake_fifo *f, const void *data, unsigned int size, void *param) {
+    struct fake_elem *e;
+    unsigned int n = elem_size(f);
+
+    if (f->state != FIFO_ALLOCATED) {
+        return NC_UNAUTHORIZED;
+    }
+
+    if (f->count == f->capacity) {
+        return NC_OUT_OF_MEMORY;
+    }
+
+    e = &f->elems[(f->head + f->count) % FAKE_MAX_ELEMS];
+    e->data = calloc(1, n);
+    memcpy(e->data, data, size < n ? size : n);
+    e->param = param;
+    f->count++;
+    pthread_cond_broadcast(&changed);
+
+    return NC_OK;
+}
+
+/* fifo_pop removes the first element from the FIFO and returns it, it must be called with lock held */
+static struct fake_elem fifo_pop(struct fake_fifo *f) {
+    struct fake_elem e = f->elems[f->head];
+
+    f->head = (f->head + 1) % FAKE_MAX_ELEMS;
+    f->count--;
+    pthread_cond_broadcast(&changed);
+
+    return e;
+}
+
+/* infer runs the identity network on the first element of in and appends the result to out.
+ * It waits for out to have room for the result, it must be called with lock held. */
+static ncStatus_t infer(struct fake_graph *g, struct fake_fifo *in, struct fake_fifo *out) {
+    struct timespec deadline = {0, 0};
+    struct fake_elem e;
+    ncStatus_t s;
+
+    if (g->state != GRAPH_ALLOCATED || in->state != FIFO_ALLOCATED || out->state != FIFO_ALLOCATED) {
+        return NC_UNAUTHORIZED;
+    }
+
+    if (in->count == 0) {
+        return NC_ERROR;
+    }
+
+    while (out->count == out->capacity) {
+        if (fake_wait(&deadline) != 0) {
+            return NC_TIMEOUT;
+        }
+    }
+
+    e = fifo_pop(in);
+    s = fifo_push(out, e.data, elem_size(in), e.param);
+    free(e.data);
+
+    return s;
+}
+
+ncStatus_t ncGraphQueueInference(struct ncGraphHandle_t *graphHandle,
+                                 struct ncFifoHandle_t **fifoIn, unsigned int inFifoCount,
+                                 struct ncFifoHandle_t **fifoOut, unsigned int outFifoCount) {
+    ncStatus_t s;
+
+    if (graphHandle == NULL || graphHandle->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    if (fifoIn == NULL || fifoOut == NULL || inFifoCount != 1 || outFifoCount != 1 ||
+        fifoIn[0] == NULL || fifoOut[0] == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    pthread_mutex_lock(&lock);
+    s = infer(graphHandle->private_data, fifoIn[0]->private_data, fifoOut[0]->private_data);
+    pthread_mutex_unlock(&lock);
+
+    return s;
+}
+
+ncStatus_t ncGraphQueueInferenceWithFifoElem(struct ncGraphHandle_t *graphHandle,
+                                             struct ncFifoHandle_t* fifoIn, struct ncFifoHandle_t* fifoOut,
+                                             const void *inputTensor, unsigned int *inputTensorLength,
+                                             void *userParam) {
+    struct timespec deadline = {0, 0};
+    struct fake_graph *g;
+    struct fake_fifo *in, *out;
+    ncStatus_t s;
+
+    if (graphHandle == NULL || fifoIn == NULL || fifoOut == NULL ||
+        graphHandle->private_data == NULL || fifoIn->private_data == NULL || fifoOut->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    if (inputTensor == NULL || inputTensorLength == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    g = graphHandle->private_data;
+    in = fifoIn->private_data;
+    out = fifoOut->private_data;
+    pthread_mutex_lock(&lock);
+
+    if (in->state == FIFO_ALLOCATED && *inputTensorLength != elem_size(in)) {
+        *inputTensorLength = elem_size(in);
+        pthread_mutex_unlock(&lock);
+        return NC_INVALID_DATA_LENGTH;
+    }
+
+    /* the element is written and inferred at once, so it's never left behind in the inbound FIFO */
+    while (in->state == FIFO_ALLOCATED && out->state == FIFO_ALLOCATED &&
+           (in->count == in->capacity || out->count == out->capacity)) {
+        if (fake_wait(&deadline) != 0) {
+            pthread_mutex_unlock(&lock);
+            return NC_TIMEOUT;
+        }
+    }
+
+    s = fifo_push(in, inputTensor, *inputTensorLength, userParam);
+    if (s == NC_OK) {
+        s = infer(g, in, out);
+    }
+
+    pthread_mutex_unlock(&lock);
+
+    return s;
+}
+
+ncStatus_t ncGraphDestroy(struct ncGraphHandle_t **graphHandle) {
+    struct fake_graph *g;
+
+    if (graphHandle == NULL || *graphHandle == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    g = (*graphHandle)->private_data;
+    pthread_mutex_lock(&lock);
+    if (g->device >= 0) {
+        graphs[g->device]--;
+    }
+    pthread_mutex_unlock(&lock);
+
+    free(g);
+    free(*graphHandle);
+    *graphHandle = NULL;
+
+    return NC_OK;
+}
+
+ncStatus_t ncFifoCreate(const char *name, ncFifoType_t type, struct ncFifoHandle_t **fifoHandle) {
+    if (name == NULL || fifoHandle == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    *fifoHandle = fifo_new(name, type);
+
+    return NC_OK;
+}
+
+ncStatus_t ncFifoAllocate(struct ncFifoHandle_t* fifoHandle, struct ncDeviceHandle_t* device,
+                          struct ncTensorDescriptor_t* tensorDesc, unsigned int numElem) {
+    ncStatus_t s;
+
+    if (fifoHandle == NULL || device == NULL || fifoHandle->private_data == NULL || device->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    if (tensorDesc == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    pthread_mutex_lock(&lock);
+    s = fifo_allocate(fifoHandle->private_data, device->private_data, tensorDesc, numElem);
+    pthread_mutex_unlock(&lock);
+
+    return s;
+}
+
+ncStatus_t ncFifoSetOption(struct ncFifoHandle_t* fifoHandle, int option, const void *data, unsigned int dataLength) {
+    struct fake_fifo *f;
+    ncStatus_t s = NC_OK;
+
+    if (fifoHandle == NULL || fifoHandle->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    if (data == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    f = fifoHandle->private_data;
+    pthread_mutex_lock(&lock);
+
+    if (option == 11) {
+        if (dataLength != sizeof(struct ncTensorDescriptor_t)) {
+            s = NC_INVALID_DATA_LENGTH;
+        } else {
+            memcpy(&f->host_desc, data, dataLength);
+        }
+    } else if (f->state != FIFO_CREATED) {
+        s = NC_UNAUTHORIZED;
+    } else if (dataLength != sizeof(int)) {
+        s = NC_INVALID_DATA_LENGTH;
+    } else {
+        switch (option) {
+        case 0:
+            f->type = *(const int *)data;
+            break;
+        case 1:
+            f->consumers = *(const int *)data;
+            break;
+        case 2:
+            f->data_type = *(const int *)data;
+            break;
+        default:
+            s = NC_INVALID_PARAMETERS;
+        }
+    }
+
+    pthread_mutex_unlock(&lock);
+
+    return s;
+}
+
+ncStatus_t ncFifoGetOption(struct ncFifoHandle_t* fifoHandle, int option, void *data, unsigned int *dataLength) {
+    struct fake_fifo *f;
+    ncStatus_t s;
+
+    if (fifoHandle == NULL || fifoHandle->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    f = fifoHandle->private_data;
+    pthread_mutex_lock(&lock);
+
+    switch (option) {
+    case 0:
+        s = reply_uint(f->type, data, dataLength);
+        break;
+    case 1:
+        s = reply_uint(f->consumers, data, dataLength);
+        break;
+    case 2:
+        s = reply_uint(f->data_type, data, dataLength);
+        break;
+    case 3:
+        s = reply_uint(0, data, dataLength);
+        break;
+    case 4:
+        s = reply_uint(f->capacity, data, dataLength);
+        break;
+    case 5:
+        s = reply_uint(f->type == NC_FIFO_HOST_RO ? f->count : 0, data, dataLength);
+        break;
+    case 6:
+        s = reply_uint(f->type == NC_FIFO_HOST_WO ? f->count : 0, data, dataLength);
+        break;
+    case 7:
+        s = reply(&f->graph_desc, sizeof(f->graph_desc), data, dataLength);
+        break;
+    case 8:
+        s = reply_uint(f->state, data, dataLength);
+        break;
+    case 9:
+        s = reply_string(f->name, data, dataLength);
+        break;
+    case 10:
+        s = reply_uint(elem_size(f), data, dataLength);
+        break;
+    case 11:
+        s = reply(&f->host_desc, sizeof(f->host_desc), data, dataLength);
+        break;
+    default:
+        s = NC_INVALID_PARAMETERS;
+    }
+
+    pthread_mutex_unlock(&lock);
+
+    return s;
+}
+
+ncStatus_t ncFifoWriteElem(struct ncFifoHandle_t* fifoHandle, const void *inputTensor,
+                           unsigned int * inputTensorLength, void *userParam) {
+    struct timespec deadline = {0, 0};
+    struct fake_fifo *f;
+    ncStatus_t s;
+
+    if (fifoHandle == NULL || fifoHandle->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    if (inputTensor == NULL || inputTensorLength == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    f = fifoHandle->private_data;
+    pthread_mutex_lock(&lock);
+
+    if (f->state == FIFO_ALLOCATED && *inputTensorLength != elem_size(f)) {
+        *inputTensorLength = elem_size(f);
+        pthread_mutex_unlock(&lock);
+        return NC_INVALID_DATA_LENGTH;
+    }
+
+    while (f->state == FIFO_ALLOCATED && f->count == f->capacity) {
+        if (fake_wait(&deadline) != 0) {
+            pthread_mutex_unlock(&lock);
+            return NC_TIMEOUT;
+        }
+    }
+
+    s = fifo_push(f, inputTensor, *inputTensorLength, userParam);
+    pthread_mutex_unlock(&lock);
+
+    return s;
+}
+
+ncStatus_t ncFifoReadElem(struct ncFifoHandle_t* fifoHandle, void *outputData,
+                          unsigned int* outputDataLen, void **userParam) {
+    struct fake_fifo *f;
+    struct timespec deadline = {0, 0};
+    struct fake_elem e;
+    ncStatus_t s = NC_OK;
+    int delay;
+
+    if (fifoHandle == NULL || fifoHandle->private_data == NULL) {
+        return NC_INVALID_HANDLE;
+    }
+
+    if (outputData == NULL || outputDataLen == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    if ((delay = env_int("MVNC_FAKE_READ_DELAY_MS", 0)) > 0) {
+        usleep(delay * 1000);
+    }
+
+    f = fifoHandle->private_data;
+    pthread_mutex_lock(&lock);
+
+    if (f->state != FIFO_ALLOCATED) {
+        pthread_mutex_unlock(&lock);
+        return NC_UNAUTHORIZED;
+    }
+
+    if (*outputDataLen != elem_size(f)) {
+        *outputDataLen = elem_size(f);
+        pthread_mutex_unlock(&lock);
+        return NC_INVALID_DATA_LENGTH;
+    }
+
+    while (f->count == 0) {
+        if (fake_wait(&deadline) != 0) {
+            break;
+        }
+    }
+
+    if (f->count == 0) {
+        s = NC_TIMEOUT;
+    } else {
+        e = fifo_pop(f);
+        memcpy(outputData, e.data, *outputDataLen);
+        if (userParam != NULL) {
+            *userParam = e.param;
+        }
+        free(e.data);
+    }
+
+    pthread_mutex_unlock(&lock);
+
+    return s;
+}
+
+ncStatus_t ncFifoDestroy(struct ncFifoHandle_t** fifoHandle) {
+    struct fake_fifo *f;
+
+    if (fifoHandle == NULL || *fifoHandle == NULL) {
+        return NC_INVALID_PARAMETERS;
+    }
+
+    f = (*fifoHandle)->private_data;
+    pthread_mutex_lock(&lock);
+    if (f->device >= 0) {
+        fifos[f->device]--;
+    }
+    while (f->count > 0) {
+        free(fifo_pop(f).data);
+    }
+    pthread_mutex_unlock(&lock);
+
+    free(f);
+    free(*fifoHandle);
+    *fifoHandle = NULL;
+
+    return NC_OK;
+}
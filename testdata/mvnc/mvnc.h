@@ -0,0 +1,111 @@
+/*
+ * Fake NCSDK2 API header used by the test suite.
+ *
+ * It declares the subset of the NCSDK2 mvnc.h API which is used by ncs.cpp.
+ * The enum values and structure layouts match the NCSDK2 header so the Go
+ * bindings behave the same against the fake as against libmvnc.
+ */
+#ifndef __MVNC_FAKE_H_INCLUDED__
+#define __MVNC_FAKE_H_INCLUDED__
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+typedef enum {
+    NC_OK = 0,
+    NC_BUSY = -1,
+    NC_ERROR = -2,
+    NC_OUT_OF_MEMORY = -3,
+    NC_DEVICE_NOT_FOUND = -4,
+    NC_INVALID_PARAMETERS = -5,
+    NC_TIMEOUT = -6,
+    NC_MVCMD_NOT_FOUND = -7,
+    NC_NOT_ALLOCATED = -8,
+    NC_UNAUTHORIZED = -9,
+    NC_UNSUPPORTED_GRAPH_FILE = -10,
+    NC_UNSUPPORTED_CONFIGURATION_FILE = -11,
+    NC_UNSUPPORTED_FEATURE = -12,
+    NC_MYRIAD_ERROR = -13,
+    NC_INVALID_DATA_LENGTH = -14,
+    NC_INVALID_HANDLE = -15
+} ncStatus_t;
+
+typedef enum {
+    NC_FIFO_HOST_RO = 0,
+    NC_FIFO_HOST_WO = 1
+} ncFifoType_t;
+
+typedef enum {
+    NC_FIFO_FP16 = 0,
+    NC_FIFO_FP32 = 1
+} ncFifoDataType_t;
+
+struct ncTensorDescriptor_t {
+    unsigned int n;
+    unsigned int c;
+    unsigned int w;
+    unsigned int h;
+    unsigned int totalSize;
+    unsigned int cStride;
+    unsigned int wStride;
+    unsigned int hStride;
+    ncFifoDataType_t dataType;
+};
+
+struct ncDeviceHandle_t {
+    void* private_data;
+};
+
+struct ncGraphHandle_t {
+    void* private_data;
+};
+
+struct ncFifoHandle_t {
+    void* private_data;
+};
+
+ncStatus_t ncGlobalSetOption(int option, const void *data, unsigned int dataLength);
+ncStatus_t ncGlobalGetOption(int option, void *data, unsigned int *dataLength);
+
+ncStatus_t ncDeviceCreate(int index, struct ncDeviceHandle_t **deviceHandle);
+ncStatus_t ncDeviceOpen(struct ncDeviceHandle_t *deviceHandle);
+ncStatus_t ncDeviceGetOption(struct ncDeviceHandle_t *deviceHandle, int option, void *data, unsigned int *dataLength);
+ncStatus_t ncDeviceClose(struct ncDeviceHandle_t *deviceHandle);
+ncStatus_t ncDeviceDestroy(struct ncDeviceHandle_t **deviceHandle);
+
+ncStatus_t ncGraphCreate(const char* name, struct ncGraphHandle_t **graphHandle);
+ncStatus_t ncGraphAllocate(struct ncDeviceHandle_t *deviceHandle, struct ncGraphHandle_t *graphHandle,
+                           const void *graphBuffer, unsigned int graphBufferLength);
+ncStatus_t ncGraphAllocateWithFifosEx(struct ncDeviceHandle_t *deviceHandle, struct ncGraphHandle_t *graphHandle,
+                                      const void *graphBuffer, unsigned int graphBufferLength,
+                                      struct ncFifoHandle_t **inFifoHandle, ncFifoType_t inFifoType,
+                                      int inNumElem, ncFifoDataType_t inDataType,
+                                      struct ncFifoHandle_t **outFifoHandle, ncFifoType_t outFifoType,
+                                      int outNumElem, ncFifoDataType_t outDataType);
+ncStatus_t ncGraphGetOption(struct ncGraphHandle_t *graphHandle, int option, void *data, unsigned int *dataLength);
+ncStatus_t ncGraphQueueInference(struct ncGraphHandle_t *graphHandle,
+                                 struct ncFifoHandle_t **fifoIn, unsigned int inFifoCount,
+                                 struct ncFifoHandle_t **fifoOut, unsigned int outFifoCount);
+ncStatus_t ncGraphQueueInferenceWithFifoElem(struct ncGraphHandle_t *graphHandle,
+                                             struct ncFifoHandle_t* fifoIn, struct ncFifoHandle_t* fifoOut,
+                                             const void *inputTensor, unsigned int *inputTensorLength,
+                                             void *userParam);
+ncStatus_t ncGraphDestroy(struct ncGraphHandle_t **graphHandle);
+
+ncStatus_t ncFifoCreate(const char *name, ncFifoType_t type, struct ncFifoHandle_t **fifoHandle);
+ncStatus_t ncFifoAllocate(struct ncFifoHandle_t* fifoHandle, struct ncDeviceHandle_t* device,
+                          struct ncTensorDescriptor_t* tensorDesc, unsigned int numElem);
+ncStatus_t ncFifoSetOption(struct ncFifoHandle_t* fifoHandle, int option, const void *data, unsigned int dataLength);
+ncStatus_t ncFifoGetOption(struct ncFifoHandle_t* fifoHandle, int option, void *data, unsigned int *dataLength);
+ncStatus_t ncFifoWriteElem(struct ncFifoHandle_t* fifoHandle, const void *inputTensor,
+                           unsigned int * inputTensorLength, void *userParam);
+ncStatus_t ncFifoReadElem(struct ncFifoHandle_t* fifoHandle, void *outputData,
+                          unsigned int* outputDataLen, void **userParam);
+ncStatus_t ncFifoDestroy(struct ncFifoHandle_t** fifoHandle);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif
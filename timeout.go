@@ -0,0 +1,153 @@
+package ncs
+
+// #cgo LDFLAGS: -lmvnc
+/*
+#include <ncs.h>
+*/
+import "C"
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultTimeout stores the package default timeout in nanoseconds
+var defaultTimeout int64
+
+// SetDefaultTimeout sets the default timeout of potentially blocking NCSDK calls such as
+// opening the device, queueing inference or reading and writing FIFO elements.
+// The default applies to all handles which do not have their own timeout set via SetTimeout.
+// Zero duration, which is the default, means the calls block until the NCSDK returns.
+//
+// NCSDK calls can not be interrupted: when a call times out, the function returns ErrCallAbandoned,
+// but the call itself keeps running in the background until the NCSDK returns. The abandoned call is
+// not retried and the handles it uses can't be closed or destroyed until it returns: Close and Destroy
+// block until then.
+func SetDefaultTimeout(d time.Duration) {
+	atomic.StoreInt64(&defaultTimeout, int64(d))
+}
+
+// DefaultTimeout returns the package default timeout of potentially blocking NCSDK calls
+func DefaultTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&defaultTimeout))
+}
+
+// callTimeout returns t if it's positive, otherwise it returns the package default timeout
+func callTimeout(t time.Duration) time.Duration {
+	if t > 0 {
+		return t
+	}
+
+	return DefaultTimeout()
+}
+
+// pin counts the NCSDK calls which are in flight on a handle.
+// Calls abandoned by a timeout or a cancelled context keep using the handle until NCSDK returns,
+// and graph and FIFO calls don't lock their device, so the handle must not be closed or destroyed
+// until all of them have returned.
+type pin struct {
+	mu   sync.Mutex
+	n    int
+	done chan struct{}
+}
+
+// acquire registers a call in flight. It does nothing if p is nil.
+func (p *pin) acquire() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.n == 0 {
+		p.done = make(chan struct{})
+	}
+	p.n++
+}
+
+// release unregisters a call which has returned. It does nothing if p is nil.
+func (p *pin) release() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.n--
+	if p.n == 0 {
+		close(p.done)
+		p.done = nil
+	}
+}
+
+// wait blocks until all the calls registered with p have returned or until ctx is done.
+// It returns ctx error if ctx is done first.
+func (p *pin) wait(ctx context.Context) error {
+	p.mu.Lock()
+	done := p.done
+	p.mu.Unlock()
+
+	if done == nil {
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// monitor runs fn and waits at most timeout for it to return or until ctx is done.
+// If fn does not return in time, monitor returns ErrCallAbandoned, if ctx is done first it returns ctx error.
+// In both cases fn is left running in the background and the handles it uses remain pinned by pins
+// until it returns, so they can't be closed or destroyed while NCSDK is still using them.
+// Abandoned calls are never retried as they may still succeed in the background.
+// If timeout is not positive and ctx can't be cancelled monitor calls fn directly, with the handles still pinned.
+func monitor(ctx context.Context, timeout time.Duration, fn func() C.int, pins ...*pin) (C.int, error) {
+	if err := ctx.Err(); err != nil {
+		return C.int(StatusOK), err
+	}
+
+	for _, p := range pins {
+		p.acquire()
+	}
+
+	release := func() {
+		for _, p := range pins {
+			p.release()
+		}
+	}
+
+	if timeout <= 0 && ctx.Done() == nil {
+		defer release()
+		return fn(), nil
+	}
+
+	done := make(chan C.int, 1)
+	go func() {
+		s := fn()
+		release()
+		done <- s
+	}()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case s := <-done:
+		return s, nil
+	case <-expired:
+		return C.int(StatusOK), ErrCallAbandoned
+	case <-ctx.Done():
+		return C.int(StatusOK), ctx.Err()
+	}
+}
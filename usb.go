@@ -0,0 +1,106 @@
+package ncs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MovidiusVendorID is the USB vendor ID of Intel Movidius devices
+const MovidiusVendorID = 0x03e7
+
+const (
+	// usbProductMA2450 is the USB product ID of unbooted MA2450 (Neural Compute Stick) devices
+	usbProductMA2450 = 0x2150
+	// usbProductMA2480 is the USB product ID of unbooted MA2480 (Neural Compute Stick 2) devices
+	usbProductMA2480 = 0x2485
+	// usbProductBooted is the USB product ID of devices which have been booted with the NCSDK firmware
+	usbProductBooted = 0xf63b
+)
+
+// USBDevice is a Movidius device attached to the host USB bus.
+// USB devices are discovered independently of NCSDK, so they include the devices NCSDK can not see,
+// e.g. the devices which are not accessible to the current user or which have not been passed through to a container.
+type USBDevice struct {
+	// Path is the USB port path of the device, e.g. 1-1.2
+	Path string `json:"path"`
+	// Bus is the USB bus number
+	Bus int `json:"bus"`
+	// Address is the USB device address on the bus
+	Address int `json:"address"`
+	// ProductID is the USB product ID of the device
+	ProductID uint16 `json:"product_id"`
+	// Booted is true if the device has been booted with the NCSDK firmware. Booted devices are in use
+	// by a process or have been left booted by a process which did not close them.
+	Booted bool `json:"booted"`
+	// Model is the device model. It is only known for devices which have not been booted yet.
+	Model string `json:"model,omitempty"`
+	// DevNode is the path of the device node used to access the device, e.g. /dev/bus/usb/001/004
+	DevNode string `json:"dev_node"`
+	// Serial is the USB serial number of the device, it is empty if the device does not report it
+	Serial string `json:"serial,omitempty"`
+}
+
+// newUSBDevice returns USBDevice with the given USB product ID and fills in the fields derived from it
+func newUSBDevice(path string, bus, addr int, product uint16) USBDevice {
+	d := USBDevice{
+		Path:      path,
+		Bus:       bus,
+		Address:   addr,
+		ProductID: product,
+		Booted:    product == usbProductBooted,
+	}
+
+	switch product {
+	case usbProductMA2450:
+		d.Model = MA2450.String()
+	case usbProductMA2480:
+		d.Model = MA2480.String()
+	}
+
+	return d
+}
+
+// serialDeviceIndex returns the NCSDK index of the device with the given USB serial number.
+// It looks the device up on the USB bus and matches its USB port path against the names of the devices NCSDK can see.
+// It returns error wrapping ErrDeviceNotFound if there is no such device.
+func serialDeviceIndex(serial string) (int, error) {
+	usbDevices, err := USBDevices()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, ud := range usbDevices {
+		if ud.Serial != serial {
+			continue
+		}
+
+		devices, err := Devices()
+		if err != nil {
+			return 0, err
+		}
+
+		for _, dd := range devices {
+			if usbNameMatches(dd.Name, ud.Path) {
+				return dd.Index, nil
+			}
+		}
+
+		return 0, fmt.Errorf("Device with serial %s at %s not visible to NCSDK: %w", serial, ud.Path, ErrDeviceNotFound)
+	}
+
+	return 0, fmt.Errorf("Device with serial %s not found: %w", serial, ErrDeviceNotFound)
+}
+
+// usbNameMatches returns true if NCSDK device name refers to the device at USB port path, e.g. 1-1.2.
+// NCSDK names the devices after the port numbers of their USB path, optionally prefixed by the bus number
+// and suffixed by the device model, e.g. 1.2 or 1.1.2-ma2480.
+func usbNameMatches(name, path string) bool {
+	bus, ports, ok := strings.Cut(path, "-")
+	if !ok || name == "" {
+		return false
+	}
+
+	name, _, _ = strings.Cut(name, "-")
+
+	return name == ports || name == bus+"."+ports
+}
@@ -0,0 +1,94 @@
+package ncs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sysfsUSBDevices is the sysfs directory which lists the USB devices
+const sysfsUSBDevices = "/sys/bus/usb/devices"
+
+// USBDevices returns all the Movidius devices attached to the host USB bus, ordered by their USB path.
+// The devices are discovered by inspecting sysfs, which helps to diagnose StatusDeviceNotFound errors,
+// e.g. when running inside containers: if the device is listed, but NCSDK can not find it, make sure
+// its DevNode is accessible to the current user. If no device is listed, the device is not attached
+// or the USB bus is not visible, e.g. /sys is not mounted into the container.
+// It returns error if it fails to read sysfs.
+func USBDevices() ([]USBDevice, error) {
+	entries, err := os.ReadDir(sysfsUSBDevices)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []USBDevice
+	for _, e := range entries {
+		dir := filepath.Join(sysfsUSBDevices, e.Name())
+
+		vendor, err := readSysfsHex(dir, "idVendor")
+		if err != nil || vendor != MovidiusVendorID {
+			// interfaces and root hubs do not have vendor IDs
+			continue
+		}
+
+		product, err := readSysfsHex(dir, "idProduct")
+		if err != nil {
+			return nil, err
+		}
+
+		bus, err := readSysfsInt(dir, "busnum")
+		if err != nil {
+			return nil, err
+		}
+
+		addr, err := readSysfsInt(dir, "devnum")
+		if err != nil {
+			return nil, err
+		}
+
+		d := newUSBDevice(e.Name(), bus, addr, uint16(product))
+		d.DevNode = fmt.Sprintf("/dev/bus/usb/%03d/%03d", bus, addr)
+		// not all the devices report their serial numbers
+		d.Serial, _ = readSysfs(dir, "serial")
+		devices = append(devices, d)
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Path < devices[j].Path
+	})
+
+	return devices, nil
+}
+
+// readSysfs reads sysfs attribute attr of device in directory dir
+func readSysfs(dir, attr string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, attr))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readSysfsHex reads hexadecimal sysfs attribute attr of device in directory dir
+func readSysfsHex(dir, attr string) (uint64, error) {
+	val, err := readSysfs(dir, attr)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(val, 16, 16)
+}
+
+// readSysfsInt reads decimal sysfs attribute attr of device in directory dir
+func readSysfsInt(dir, attr string) (int, error) {
+	val, err := readSysfs(dir, attr)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(val)
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package ncs
+
+import "fmt"
+
+// USBDevices returns all the Movidius devices attached to the host USB bus.
+// It is only supported on Linux, on other platforms it returns error wrapping ErrUnsupportedFeature.
+func USBDevices() ([]USBDevice, error) {
+	return nil, fmt.Errorf("USB device discovery not supported on this platform: %w", ErrUnsupportedFeature)
+}
@@ -0,0 +1,26 @@
+package ncs
+
+import "testing"
+
+func TestUSBNameMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "1.2", path: "1-1.2", want: true},
+		{name: "1.1.2", path: "1-1.2", want: true},
+		{name: "1.2-ma2480", path: "1-1.2", want: true},
+		{name: "2.1.2-ma2450", path: "2-1.2", want: true},
+		{name: "1.3", path: "1-1.2", want: false},
+		{name: "2.1.2", path: "1-1.2", want: false},
+		{name: "", path: "1-1.2", want: false},
+		{name: "1.2", path: "usb1", want: false},
+	}
+
+	for _, tc := range tests {
+		if got := usbNameMatches(tc.name, tc.path); got != tc.want {
+			t.Errorf("usbNameMatches(%q, %q): expected %v, got: %v", tc.name, tc.path, tc.want, got)
+		}
+	}
+}
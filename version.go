@@ -0,0 +1,104 @@
+package ncs
+
+import (
+	"runtime/debug"
+	"sort"
+)
+
+// modulePath is the import path of this package module
+const modulePath = "github.com/milosgajdos/ncs"
+
+// BuildInfo is the version fingerprint of the whole NCS stack used by the application
+type BuildInfo struct {
+	// Bindings is the version of this package as recorded in the application build info.
+	// It is "(devel)" if the version is not known, e.g. when the package is built from a local checkout.
+	Bindings string `json:"bindings"`
+	// API is the version of the linked NCSDK API library
+	API VersionNumber `json:"api"`
+	// Devices contains the firmware versions of all the opened devices ordered by device index
+	Devices []DeviceVersion `json:"devices"`
+}
+
+// DeviceVersion is the firmware version of an opened device
+type DeviceVersion struct {
+	// Index is the index of the device
+	Index int `json:"index"`
+	// Firmware is the version of the firmware running on the device
+	Firmware VersionNumber `json:"firmware"`
+}
+
+// bindingsVersion returns the version of this package recorded in the build info
+func bindingsVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+
+	return moduleVersion(info)
+}
+
+// moduleVersion returns the version of this package recorded in info.
+// If the package module is replaced by a local directory, which has no version,
+// it returns the version of the replaced module. It returns "(devel)" if no version is recorded.
+func moduleVersion(info *debug.BuildInfo) string {
+	version := ""
+
+	if info.Main.Path == modulePath {
+		version = info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			version = dep.Version
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				version = dep.Replace.Version
+			}
+		}
+	}
+
+	if version == "" {
+		return "(devel)"
+	}
+
+	return version
+}
+
+// Version returns the version of this package along with the version of the linked NCSDK API library
+// and the firmware versions of all the devices which are currently opened.
+// This is useful for bug reports and telemetry.
+// It returns error if it fails to query any of the versions.
+func Version() (*BuildInfo, error) {
+	api, err := APIVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &BuildInfo{
+		Bindings: bindingsVersion(),
+		API:      api,
+	}
+
+	devices, _, _ := liveHandles()
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].index < devices[j].index
+	})
+
+	for _, d := range devices {
+		d.mu.RLock()
+		opened := d.handle != nil && d.state == DeviceOpened
+		d.mu.RUnlock()
+
+		if !opened {
+			continue
+		}
+
+		fw, err := firmwareVersion(d)
+		if err != nil {
+			return nil, err
+		}
+
+		info.Devices = append(info.Devices, DeviceVersion{Index: d.index, Firmware: fw})
+	}
+
+	return info, nil
+}
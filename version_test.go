@@ -0,0 +1,38 @@
+package ncs
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestModuleVersion(t *testing.T) {
+	dep := func(version string, replace *debug.Module) []*debug.Module {
+		return []*debug.Module{
+			{Path: "golang.org/x/image", Version: "v0.1.0"},
+			{Path: modulePath, Version: version, Replace: replace},
+		}
+	}
+
+	tests := []struct {
+		name string
+		info *debug.BuildInfo
+		want string
+	}{
+		{name: "Main", info: &debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "v1.2.3"}}, want: "v1.2.3"},
+		{name: "MainDevel", info: &debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "(devel)"}}, want: "(devel)"},
+		{name: "MainEmpty", info: &debug.BuildInfo{Main: debug.Module{Path: modulePath}}, want: "(devel)"},
+		{name: "Dep", info: &debug.BuildInfo{Deps: dep("v1.0.0", nil)}, want: "v1.0.0"},
+		{name: "Replace", info: &debug.BuildInfo{Deps: dep("v1.0.0", &debug.Module{Path: "example.com/ncs", Version: "v1.0.1"})}, want: "v1.0.1"},
+		{name: "ReplaceDir", info: &debug.BuildInfo{Deps: dep("v1.0.0", &debug.Module{Path: "../ncs"})}, want: "v1.0.0"},
+		{name: "ReplaceDirNoVersion", info: &debug.BuildInfo{Deps: dep("", &debug.Module{Path: "../ncs"})}, want: "(devel)"},
+		{name: "Missing", info: &debug.BuildInfo{Main: debug.Module{Path: "example.com/app", Version: "v0.1.0"}}, want: "(devel)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := moduleVersion(tc.info); got != tc.want {
+				t.Errorf("expected %q, got: %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// Package video decodes video files and streams into raw RGB frames which can be preprocessed
+// and fed to the models run on Neural Compute Stick. The decoding is done by ffmpeg,
+// which must be installed on the host, so offline video analytics does not require OpenCV.
+package video
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os/exec"
+	"strconv"
+)
+
+// Opts configures the decoded frames
+type Opts struct {
+	// Width is the width the frames are scaled to. If Width or Height is not positive, the frames are not scaled.
+	Width int
+	// Height is the height the frames are scaled to. If Width or Height is not positive, the frames are not scaled.
+	Height int
+	// FPS is the frame rate the video is resampled to. If it's not positive, the video frame rate is kept.
+	FPS float64
+	// FFmpeg is the path to the ffmpeg binary. It defaults to ffmpeg found in PATH.
+	FFmpeg string
+	// FFprobe is the path to the ffprobe binary used to query the video size when the frames are not scaled.
+	// It defaults to ffprobe found in PATH.
+	FFprobe string
+}
+
+// Reader reads decoded frames of a video as interleaved 8 bit RGB buffers
+type Reader struct {
+	cmd    *exec.Cmd
+	out    io.ReadCloser
+	stderr bytes.Buffer
+	width  int
+	height int
+	// waitErr is the ffmpeg exit error once ffmpeg has exited
+	waitErr error
+	waited  bool
+}
+
+// Open starts decoding the video file or stream URL src and returns Reader which reads its frames.
+// If opts is nil the frames are read in the video size and frame rate.
+// The Reader must be closed once it is no longer needed.
+// It returns error if it fails to start ffmpeg or to query the video size.
+func Open(src string, opts *Opts) (*Reader, error) {
+	var o Opts
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.FFmpeg == "" {
+		o.FFmpeg = "ffmpeg"
+	}
+	if o.FFprobe == "" {
+		o.FFprobe = "ffprobe"
+	}
+
+	width, height := o.Width, o.Height
+	if width <= 0 || height <= 0 {
+		var err error
+		if width, height, err = probeSize(o.FFprobe, src); err != nil {
+			return nil, err
+		}
+	}
+
+	args := []string{"-nostdin", "-loglevel", "error", "-i", src}
+
+	filter := fmt.Sprintf("scale=%d:%d", width, height)
+	if o.FPS > 0 {
+		filter = "fps=" + strconv.FormatFloat(o.FPS, 'f', -1, 64) + "," + filter
+	}
+	args = append(args, "-vf", filter, "-f", "rawvideo", "-pix_fmt", "rgb24", "-")
+
+	r := &Reader{width: width, height: height}
+	r.cmd = exec.Command(o.FFmpeg, args...)
+	r.cmd.Stderr = &r.stderr
+
+	out, err := r.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	r.out = out
+
+	if err := r.cmd.Start(); err != nil {
+		return nil, fmt.Errorf("Failed to start ffmpeg: %w", err)
+	}
+
+	return r, nil
+}
+
+// probeSize queries the size of the first video stream of src with ffprobe
+func probeSize(ffprobe, src string) (int, int, error) {
+	out, err := exec.Command(ffprobe, "-v", "error", "-select_streams", "v:0",
+		"-show_entries", "stream=width,height", "-of", "csv=p=0:s=x", src).Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("Failed to query video size: %w", err)
+	}
+
+	var width, height int
+	if _, err := fmt.Sscanf(string(bytes.TrimSpace(out)), "%dx%d", &width, &height); err != nil {
+		return 0, 0, fmt.Errorf("Failed to parse video size %q: %w", out, err)
+	}
+
+	return width, height, nil
+}
+
+// Size returns the width and height of the frames
+func (r *Reader) Size() (int, int) {
+	return r.width, r.height
+}
+
+// FrameSize returns the size of the frames in bytes
+func (r *Reader) FrameSize() int {
+	return 3 * r.width * r.height
+}
+
+// ReadFrame reads the next frame into buf, which is reused if it has enough capacity, and returns it.
+// The frame contains interleaved 8 bit RGB samples in row-major order.
+// It returns io.EOF once all the frames have been read.
+func (r *Reader) ReadFrame(buf []byte) ([]byte, error) {
+	size := r.FrameSize()
+	if cap(buf) < size {
+		buf = make([]byte, size)
+	}
+	buf = buf[:size]
+
+	if _, err := io.ReadFull(r.out, buf); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("Truncated frame: %w", err)
+		}
+		if errors.Is(err, io.EOF) {
+			if err := r.wait(); err != nil {
+				return nil, fmt.Errorf("Failed to decode video: %w: %s", err, bytes.TrimSpace(r.stderr.Bytes()))
+			}
+		}
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// ReadImage reads the next frame and returns it as image.
+// It returns io.EOF once all the frames have been read.
+func (r *Reader) ReadImage() (*image.RGBA, error) {
+	frame, err := r.ReadFrame(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, r.width, r.height))
+	for i, j := 0, 0; i < len(frame); i, j = i+3, j+4 {
+		img.Pix[j], img.Pix[j+1], img.Pix[j+2], img.Pix[j+3] = frame[i], frame[i+1], frame[i+2], 0xff
+	}
+
+	return img, nil
+}
+
+// Frames decodes the frames in the background and sends them to the returned channel, which is closed
+// once all the frames have been read, reading fails or ctx is done. The error, if any, is sent to the returned
+// error channel; if ctx is done before all the frames have been read, ctx error is sent to it.
+// This allows the frames to be decoded while the previous frames are being processed.
+//
+// The frames are decoded into a small set of rotating buffers, so a received frame is only valid
+// until the next frame is received and it must be copied if it's needed for longer.
+// To stop decoding early, cancel ctx and wait for the frames channel to be closed before calling Close.
+func (r *Reader) Frames(ctx context.Context) (<-chan []byte, <-chan error) {
+	frames := make(chan []byte, 1)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(frames)
+
+		// one buffer is held by the receiver, one is queued in the channel and one is being decoded into
+		var bufs [3][]byte
+		for i := 0; ; i = (i + 1) % len(bufs) {
+			if err := ctx.Err(); err != nil {
+				errc <- err
+				return
+			}
+
+			frame, err := r.ReadFrame(bufs[i])
+			if err != nil {
+				if err != io.EOF {
+					errc <- err
+				}
+				return
+			}
+			bufs[i] = frame
+
+			select {
+			case frames <- frame:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return frames, errc
+}
+
+// wait waits for ffmpeg to exit and returns its exit error
+func (r *Reader) wait() error {
+	if !r.waited {
+		r.waitErr = r.cmd.Wait()
+		r.waited = true
+	}
+
+	return r.waitErr
+}
+
+// Close stops decoding the video and releases ffmpeg resources.
+// It must not be called while the frames are being read, including by Frames.
+func (r *Reader) Close() error {
+	r.out.Close()
+	if err := r.wait(); err != nil {
+		// ffmpeg fails with broken pipe when it is stopped before decoding all the frames
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
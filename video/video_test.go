@@ -0,0 +1,124 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeFFmpeg writes a shell script which ignores its arguments and writes the output of cmd to stdout
+// and returns its path. It skips the test if there is no shell to run the script.
+func fakeFFmpeg(t *testing.T, cmd string) string {
+	t.Helper()
+
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("fake ffmpeg requires /bin/sh")
+	}
+
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+cmd+"\n"), 0o755); err != nil {
+		t.Fatalf("failed to write fake ffmpeg: %v", err)
+	}
+
+	return path
+}
+
+// openFake opens 2x2 video decoded by fake ffmpeg running cmd
+func openFake(t *testing.T, cmd string) *Reader {
+	t.Helper()
+
+	r, err := Open("fake.mp4", &Opts{Width: 2, Height: 2, FFmpeg: fakeFFmpeg(t, cmd)})
+	if err != nil {
+		t.Fatalf("failed to open video: %v", err)
+	}
+
+	return r
+}
+
+func TestFrames(t *testing.T) {
+	const count = 7
+
+	r := openFake(t, "head -c 84 /dev/zero")
+	defer r.Close()
+
+	frames, errc := r.Frames(context.Background())
+
+	var n int
+	seen := make(map[*byte]bool)
+	for frame := range frames {
+		if len(frame) != r.FrameSize() {
+			t.Errorf("expected frame of %d bytes, got: %d", r.FrameSize(), len(frame))
+		}
+		seen[&frame[0]] = true
+		n++
+	}
+
+	if n != count {
+		t.Errorf("expected %d frames, got: %d", count, n)
+	}
+
+	if len(seen) > 3 {
+		t.Errorf("expected frame buffers to be reused, got %d buffers", len(seen))
+	}
+
+	select {
+	case err := <-errc:
+		t.Errorf("unexpected error: %v", err)
+	default:
+	}
+}
+
+func TestFramesCancel(t *testing.T) {
+	r := openFake(t, "exec cat /dev/zero")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	frames, errc := r.Frames(ctx)
+
+	<-frames
+	cancel()
+
+	// the decoding goroutine must not block on sending the frames nobody receives
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range frames {
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("frames channel not closed after cancelling decoding")
+	}
+
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got: %v", context.Canceled, err)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Errorf("failed to close reader: %v", err)
+	}
+}
+
+func TestFramesTruncated(t *testing.T) {
+	r := openFake(t, "head -c 20 /dev/zero")
+	defer r.Close()
+
+	frames, errc := r.Frames(context.Background())
+
+	var n int
+	for range frames {
+		n++
+	}
+
+	if n != 1 {
+		t.Errorf("expected 1 frame, got: %d", n)
+	}
+
+	if err := <-errc; err == nil {
+		t.Errorf("expected error reading truncated frame")
+	}
+}